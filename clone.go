@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// cloneTag is struct tag what sets copy policy of field
+//
+// Example:
+//
+//	type GetArticles struct {
+//		// shared between all instances
+//		Cache map[string]Article `clone:"shallow"`
+//
+//		// each instance gets its own copy of pointed value
+//		Filter *Filter `clone:"deep"`
+//	}
+const cloneTag = "clone"
+
+const (
+	cloneShallow = "shallow"
+	cloneDeep    = "deep"
+)
+
+// cloner creates copies of prototype struct passed to New.
+//
+// Copy policy of fields is computed once:
+// slices, maps, arrays and structs are deep copied by default,
+// pointers, interfaces, funcs and channels are shared.
+// Policy of exported field can be changed with `clone:"shallow"`
+// or `clone:"deep"` tag. Unexported fields are always shared
+type cloner struct {
+	t reflect.Type
+
+	// nil if shallow copy of prototype is enough
+	copy *typeCopier
+}
+
+// typeCopier copies parts of src what should not be shared to dst,
+// dst already holds shallow copy of src
+type typeCopier struct {
+	fn func(dst, src reflect.Value)
+}
+
+func newCloner(t reflect.Type) (*cloner, error) {
+	b := clonerBuilder{copiers: map[reflect.Type]*typeCopier{}}
+
+	copier, err := b.typeCopier(t, t.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloner{t: t, copy: copier}, nil
+}
+
+// Prototype returns constructor what creates addressable copies of prototype struct
+// the same way as New does for struct passed to it, so `clone` tags are respected.
+// It's useful for constructors what need to keep created instance, like test harness
+//
+// Example:
+//
+//	clone, err := handler.Prototype(MyHandler{Cache: cache})
+//	h, err := handler.New(pipes, clone, converter, handler.WithType(reflect.TypeOf(MyHandler{})))
+func Prototype(prototype interface{}) (func() reflect.Value, error) {
+	v := reflect.ValueOf(prototype)
+
+	if v.Kind() != reflect.Struct {
+		return nil, ErrorPrototypeNotStruct
+	}
+
+	cloner, err := newCloner(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	return func() reflect.Value {
+		return cloner.clone(v)
+	}, nil
+}
+
+// clone returns addressable copy of prototype
+func (c *cloner) clone(prototype reflect.Value) reflect.Value {
+	instance := reflect.New(c.t).Elem()
+	instance.Set(prototype)
+
+	if c.copy != nil {
+		c.copy.fn(instance, prototype)
+	}
+
+	return instance
+}
+
+type clonerBuilder struct {
+	// copiers of types with default policy, nil if type doesn't need copying
+	copiers map[reflect.Type]*typeCopier
+}
+
+// typeCopier returns copier of type with default policy,
+// or nil if shallow copy is enough
+func (b *clonerBuilder) typeCopier(t reflect.Type, path string) (*typeCopier, error) {
+	if copier, built := b.copiers[t]; built {
+		return copier, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		// placeholder for recursive types
+		copier := &typeCopier{}
+		b.copiers[t] = copier
+
+		fields, err := b.fieldCopiers(t, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fields) == 0 {
+			b.copiers[t] = nil
+			copier.fn = func(dst, src reflect.Value) {}
+
+			return nil, nil
+		}
+
+		copier.fn = func(dst, src reflect.Value) {
+			for _, field := range fields {
+				field.copy.fn(dst.Field(field.index), src.Field(field.index))
+			}
+		}
+
+		return copier, nil
+	case reflect.Slice:
+		copier := &typeCopier{}
+		b.copiers[t] = copier
+
+		elem, err := b.typeCopier(t.Elem(), path+"[]")
+		if err != nil {
+			return nil, err
+		}
+
+		copier.fn = sliceCopier(elem)
+
+		return copier, nil
+	case reflect.Map:
+		copier := &typeCopier{}
+		b.copiers[t] = copier
+
+		elem, err := b.typeCopier(t.Elem(), path+"[]")
+		if err != nil {
+			return nil, err
+		}
+
+		copier.fn = mapCopier(elem)
+
+		return copier, nil
+	case reflect.Array:
+		elem, err := b.typeCopier(t.Elem(), path+"[]")
+		if err != nil || elem == nil {
+			b.copiers[t] = nil
+
+			return nil, err
+		}
+
+		copier := &typeCopier{fn: func(dst, src reflect.Value) {
+			for i := 0; i < src.Len(); i++ {
+				elem.fn(dst.Index(i), src.Index(i))
+			}
+		}}
+		b.copiers[t] = copier
+
+		return copier, nil
+	}
+
+	// pointers, interfaces, funcs, channels and scalars are shared
+	b.copiers[t] = nil
+
+	return nil, nil
+}
+
+type fieldCopier struct {
+	index int
+	copy  *typeCopier
+}
+
+// fieldCopiers returns copiers of struct fields what should not be shared
+func (b *clonerBuilder) fieldCopiers(t reflect.Type, path string) ([]fieldCopier, error) {
+	var fields []fieldCopier
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldPath := path + "." + field.Name
+
+		policy, tagged := field.Tag.Lookup(cloneTag)
+
+		if tagged && policy != cloneShallow && policy != cloneDeep {
+			return nil, fmt.Errorf("%w %q of field %s", ErrorCloneTag, policy, fieldPath)
+		}
+
+		if !field.IsExported() {
+			if policy == cloneDeep {
+				return nil, fmt.Errorf("%w %s", ErrorCloneUnexportedField, fieldPath)
+			}
+
+			continue
+		}
+
+		if policy == cloneShallow {
+			continue
+		}
+
+		var copier *typeCopier
+		var err error
+
+		if policy == cloneDeep {
+			copier, err = b.deepCopier(field.Type, fieldPath)
+		} else {
+			copier, err = b.typeCopier(field.Type, fieldPath)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if copier != nil {
+			fields = append(fields, fieldCopier{index: i, copy: copier})
+		}
+	}
+
+	return fields, nil
+}
+
+// deepCopier returns copier of field tagged with `clone:"deep"`
+func (b *clonerBuilder) deepCopier(t reflect.Type, path string) (*typeCopier, error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem, err := b.typeCopier(t.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &typeCopier{fn: pointerCopier(elem)}, nil
+	case reflect.Struct, reflect.Slice, reflect.Map, reflect.Array:
+		return b.typeCopier(t, path)
+	}
+
+	return nil, fmt.Errorf("%w %s of field %s", ErrorCloneDeepUnsupported, t, path)
+}
+
+func sliceCopier(elem *typeCopier) func(dst, src reflect.Value) {
+	return func(dst, src reflect.Value) {
+		if src.IsNil() {
+			return
+		}
+
+		copied := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		reflect.Copy(copied, src)
+
+		if elem != nil {
+			for i := 0; i < src.Len(); i++ {
+				elem.fn(copied.Index(i), src.Index(i))
+			}
+		}
+
+		dst.Set(copied)
+	}
+}
+
+func mapCopier(elem *typeCopier) func(dst, src reflect.Value) {
+	return func(dst, src reflect.Value) {
+		if src.IsNil() {
+			return
+		}
+
+		copied := reflect.MakeMapWithSize(src.Type(), src.Len())
+
+		iter := src.MapRange()
+		for iter.Next() {
+			value := iter.Value()
+
+			if elem != nil {
+				// map values are not addressable
+				copiedValue := reflect.New(value.Type()).Elem()
+				copiedValue.Set(value)
+				elem.fn(copiedValue, value)
+
+				value = copiedValue
+			}
+
+			copied.SetMapIndex(iter.Key(), value)
+		}
+
+		dst.Set(copied)
+	}
+}
+
+func pointerCopier(elem *typeCopier) func(dst, src reflect.Value) {
+	return func(dst, src reflect.Value) {
+		if src.IsNil() {
+			return
+		}
+
+		copied := reflect.New(src.Type().Elem())
+		copied.Elem().Set(src.Elem())
+
+		if elem != nil {
+			elem.fn(copied.Elem(), src.Elem())
+		}
+
+		dst.Set(copied)
+	}
+}
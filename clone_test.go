@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	mockServices struct {
+		Name string
+	}
+
+	mockNode struct {
+		Name     string
+		Children []mockNode
+	}
+
+	mockCloneStruct struct {
+		Slice    []string
+		Map      map[string][]int
+		Array    [2][]int
+		Nested   mockNode
+		Shared   []string `clone:"shallow"`
+		Services *mockServices
+		Deep     *mockNode `clone:"deep"`
+		Nil      []string
+		private  []string
+	}
+)
+
+func newMockCloneStruct() mockCloneStruct {
+	return mockCloneStruct{
+		Slice:    []string{"a", "b"},
+		Map:      map[string][]int{"key": {1, 2}},
+		Array:    [2][]int{{1}, {2}},
+		Nested:   mockNode{Name: "root", Children: []mockNode{{Name: "child"}}},
+		Shared:   []string{"shared"},
+		Services: &mockServices{Name: "services"},
+		Deep:     &mockNode{Name: "deep", Children: []mockNode{{Name: "deep child"}}},
+		private:  []string{"private"},
+	}
+}
+
+func Test_Cloner_DefaultPolicy_ExpectEqualButIndependentCopy(t *testing.T) {
+	prototype := newMockCloneStruct()
+
+	c, err := newCloner(reflect.TypeOf(prototype))
+	assert.NoError(t, err)
+
+	clone := c.clone(reflect.ValueOf(prototype)).Interface().(mockCloneStruct)
+
+	assert.Equal(t, prototype, clone)
+
+	clone.Slice[0] = "changed"
+	clone.Map["key"][0] = 100
+	clone.Array[0][0] = 100
+	clone.Nested.Children[0].Name = "changed"
+	clone.Deep.Children[0].Name = "changed"
+	clone.Deep.Name = "changed"
+
+	assert.Equal(t, newMockCloneStruct(), prototype)
+}
+
+func Test_Cloner_SharedFields_ExpectSameReferences(t *testing.T) {
+	prototype := newMockCloneStruct()
+
+	c, err := newCloner(reflect.TypeOf(prototype))
+	assert.NoError(t, err)
+
+	clone := c.clone(reflect.ValueOf(prototype)).Interface().(mockCloneStruct)
+
+	assert.Same(t, prototype.Services, clone.Services)
+	assert.Same(t, &prototype.Shared[0], &clone.Shared[0])
+	assert.Same(t, &prototype.private[0], &clone.private[0])
+	assert.Nil(t, clone.Nil)
+}
+
+func Test_Cloner_ReturnsAddressableValue(t *testing.T) {
+	c, err := newCloner(reflect.TypeOf(mockStruct{}))
+	assert.NoError(t, err)
+
+	assert.True(t, c.clone(reflect.ValueOf(mockStruct{Field1: "moq"})).CanAddr())
+}
+
+func Test_Prototype_ExpectAddressableIndependentCopies(t *testing.T) {
+	prototype := newMockCloneStruct()
+
+	clone, err := Prototype(prototype)
+	assert.NoError(t, err)
+
+	instance := clone()
+
+	assert.True(t, instance.CanAddr())
+
+	instance.Interface().(mockCloneStruct).Slice[0] = "changed"
+
+	assert.Equal(t, newMockCloneStruct(), prototype)
+
+	_, err = Prototype(&prototype)
+	assert.ErrorIs(t, err, ErrorPrototypeNotStruct)
+}
+
+func Test_New_InvalidCloneTags_ExpectError(t *testing.T) {
+	t.Run("Unknown policy", func(t *testing.T) {
+		_, err := New(mockPipes, struct {
+			Field []int `clone:"sometimes"`
+		}{}, converterMock)
+
+		assert.True(t, errors.Is(err, ErrorCloneTag))
+	})
+
+	t.Run("Deep copy of unexported field", func(t *testing.T) {
+		_, err := New(mockPipes, struct {
+			field *int `clone:"deep"`
+		}{}, converterMock)
+
+		assert.True(t, errors.Is(err, ErrorCloneUnexportedField))
+	})
+
+	t.Run("Deep copy of channel", func(t *testing.T) {
+		_, err := New(mockPipes, struct {
+			Field chan int `clone:"deep"`
+		}{}, converterMock)
+
+		assert.True(t, errors.Is(err, ErrorCloneDeepUnsupported))
+	})
+
+	t.Run("Nested struct", func(t *testing.T) {
+		_, err := New(mockPipes, struct {
+			Request struct {
+				Field []int `clone:"sometimes"`
+			}
+		}{}, converterMock)
+
+		assert.True(t, errors.Is(err, ErrorCloneTag))
+		assert.Contains(t, err.Error(), ".Request.Field")
+	})
+}
+
+func Test_Handler_StructPrototype_ExpectInstancesNotShareSlices(t *testing.T) {
+	var pipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		instance := v.Addr().Interface().(*mockCloneStruct)
+		instance.Slice = append(instance.Slice[:0], "changed")
+
+		return &v, nil
+	}
+
+	prototype := newMockCloneStruct()
+
+	h, err := New(PipeGroup{pipe}, prototype, converterMock)
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, prototype.Slice)
+}
+
+func Benchmark_Cloner_Shallow(b *testing.B) {
+	prototype := reflect.ValueOf(mockStruct{Field1: "moq1", Field2: "moq2"})
+
+	c, err := newCloner(prototype.Type())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.clone(prototype)
+	}
+}
+
+func Benchmark_Cloner_Deep(b *testing.B) {
+	prototype := reflect.ValueOf(newMockCloneStruct())
+
+	c, err := newCloner(prototype.Type())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.clone(prototype)
+	}
+}
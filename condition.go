@@ -0,0 +1,248 @@
+package handler
+
+import "reflect"
+
+// Predicate chooses pipes of IfGroup or SwitchGroup by handler instance and generic handler arguments
+type Predicate func(v reflect.Value, args ...interface{}) bool
+
+// Condition is predicate of IfGroup or SwitchGroup, it's Predicate or NamedCondition
+type Condition interface {
+	Match(v reflect.Value, args ...interface{}) bool
+}
+
+// Match calls predicate
+func (p Predicate) Match(v reflect.Value, args ...interface{}) bool {
+	return p(v, args...)
+}
+
+// NamedCondition is predicate with name, description and tags.
+// Name is used by dumps instead of name of predicate func
+type NamedCondition struct {
+	PipeInfo
+
+	Predicate Predicate
+}
+
+// Match calls predicate
+func (c NamedCondition) Match(v reflect.Value, args ...interface{}) bool {
+	return c.Predicate(v, args...)
+}
+
+// IfGroup runs Then pipes when predicate is true, or Else pipes otherwise.
+// Else may be nil, then nothing runs when predicate is false
+type IfGroup struct {
+	Predicate Condition
+
+	Then PipeGroup
+	Else PipeGroup
+}
+
+// If creates group what runs then or otherwise pipes depending on predicate
+//
+// Example:
+//
+//	var ActionPipes = handler.PipeGroup{
+//		handler.If(nethttp.ContentType("application/x-www-form-urlencoded"),
+//			handler.PipeGroup{nethttp.BindFormPipe},
+//			handler.PipeGroup{nethttp.BindRequestPipe},
+//		),
+//		handler.PipeArray{nethttp.CallActionPipe, nethttp.RenderPipe},
+//	}
+func If(predicate Condition, then, otherwise PipeGroup) IfGroup {
+	return IfGroup{Predicate: predicate, Then: then, Else: otherwise}
+}
+
+// Case represents pipes of SwitchGroup what run when predicate is true
+type Case struct {
+	Predicate Condition
+
+	Pipes PipeGroup
+}
+
+// SwitchGroup runs pipes of the first case what predicate is true,
+// or Default pipes when there is no such case.
+// Default may be nil, then nothing runs
+type SwitchGroup struct {
+	Cases []Case
+
+	Default PipeGroup
+}
+
+// Switch creates group what runs pipes of the first matching case
+//
+// Example:
+//
+//	handler.Switch(
+//		handler.Case{Predicate: handler.FieldSet("V2"), Pipes: handler.PipeGroup{MigrateV2Pipe}},
+//		handler.Case{Predicate: handler.FieldSet("V1"), Pipes: handler.PipeGroup{MigrateV1Pipe}},
+//	)
+func Switch(cases ...Case) SwitchGroup {
+	return SwitchGroup{Cases: cases}
+}
+
+// NamedPredicate returns predicate with name, description and tags
+func NamedPredicate(name string, predicate Predicate, options ...PipeOption) NamedCondition {
+	return NamedCondition{PipeInfo: newPipeInfo(name, options), Predicate: predicate}
+}
+
+// String returns name of predicate
+func (p Predicate) String() string {
+	return pipeName(p)
+}
+
+// FieldSet returns predicate what is true when field of handler instance has non-zero value
+func FieldSet(field string) NamedCondition {
+	return NamedPredicate("FieldSet("+field+")", func(v reflect.Value, args ...interface{}) bool {
+		v = reflect.Indirect(v)
+
+		if v.Kind() != reflect.Struct {
+			return false
+		}
+
+		f := v.FieldByName(field)
+
+		return f.IsValid() && !f.IsZero()
+	})
+}
+
+// conditionBranch is pipes of IfGroup or SwitchGroup in dumps
+type conditionBranch struct {
+	label string
+	pipes PipeGroup
+}
+
+// conditionBranches returns pipes of IfGroup or SwitchGroup in order of paths
+func conditionBranches(node interface{}) []conditionBranch {
+	var branches []conditionBranch
+
+	switch node := node.(type) {
+	case IfGroup:
+		branches = append(branches, conditionBranch{label: "then", pipes: node.Then})
+
+		if node.Else != nil {
+			branches = append(branches, conditionBranch{label: "else", pipes: node.Else})
+		}
+	case SwitchGroup:
+		for _, c := range node.Cases {
+			branches = append(branches, conditionBranch{label: "case " + predicateLabel(c.Predicate), pipes: c.Pipes})
+		}
+
+		if node.Default != nil {
+			branches = append(branches, conditionBranch{label: "default", pipes: node.Default})
+		}
+	}
+
+	return branches
+}
+
+// predicateLabel returns name of predicate with tags
+func predicateLabel(predicate Condition) string {
+	if conditionNil(predicate) {
+		return "nil"
+	}
+
+	return InfoOf(predicate).String()
+}
+
+// conditionNil reports whether condition or its predicate func is nil
+func conditionNil(condition Condition) bool {
+	switch c := condition.(type) {
+	case nil:
+		return true
+	case Predicate:
+		return c == nil
+	case NamedCondition:
+		return c.Predicate == nil
+	}
+
+	return false
+}
+
+// validateIf checks predicate and pipes of IfGroup
+func validateIf(group IfGroup, path Path) (IfGroup, error) {
+	if conditionNil(group.Predicate) {
+		return IfGroup{}, &TreeError{Path: path, Value: group, Err: ErrorPredicateNil}
+	}
+
+	then, err := validateGroup(group.Then, childPath(path, 0))
+	if err != nil {
+		return IfGroup{}, err
+	}
+
+	validated := IfGroup{Predicate: group.Predicate, Then: then}
+
+	if group.Else != nil {
+		if validated.Else, err = validateGroup(group.Else, childPath(path, 1)); err != nil {
+			return IfGroup{}, err
+		}
+	}
+
+	return validated, nil
+}
+
+// validateSwitch checks cases and pipes of SwitchGroup
+func validateSwitch(group SwitchGroup, path Path) (SwitchGroup, error) {
+	if len(group.Cases) == 0 {
+		return SwitchGroup{}, &TreeError{Path: path, Value: group, Err: ErrorPipeGroupEmpty}
+	}
+
+	validated := SwitchGroup{Cases: make([]Case, len(group.Cases))}
+
+	for i, c := range group.Cases {
+		if conditionNil(c.Predicate) {
+			return SwitchGroup{}, &TreeError{Path: childPath(path, i), Value: c, Err: ErrorPredicateNil}
+		}
+
+		pipes, err := validateGroup(c.Pipes, childPath(path, i))
+		if err != nil {
+			return SwitchGroup{}, err
+		}
+
+		validated.Cases[i] = Case{Predicate: c.Predicate, Pipes: pipes}
+	}
+
+	if group.Default != nil {
+		pipes, err := validateGroup(group.Default, childPath(path, len(group.Cases)))
+		if err != nil {
+			return SwitchGroup{}, err
+		}
+
+		validated.Default = pipes
+	}
+
+	return validated, nil
+}
+
+// conditional compiles group what runs one of groups chosen by predicates, or fallback
+func (c *compiler) conditional(node interface{}, path Path, predicates []Condition, groups []PipeGroup, fallback PipeGroup) {
+	c.group(instruction{op: opEnter, path: path, node: node})
+
+	choose := c.emit(instruction{op: opSwitch, predicates: predicates, path: path, node: node})
+
+	// the last target is fallback, or exit of group when there is no fallback
+	targets := make([]int, len(groups)+1)
+	jumps := make([]int, len(groups))
+
+	for i, group := range groups {
+		targets[i] = len(c.plan)
+
+		c.node(group, childPath(path, i))
+
+		jumps[i] = c.emit(instruction{op: opJump, path: path, node: node})
+	}
+
+	targets[len(groups)] = len(c.plan)
+
+	if fallback != nil {
+		c.node(fallback, childPath(path, len(groups)))
+	}
+
+	exit := c.group(instruction{op: opExit, path: path, node: node})
+
+	// chosen group skips other groups
+	for _, jump := range jumps {
+		c.plan[jump].jump = exit
+	}
+
+	c.plan[choose].targets = targets
+}
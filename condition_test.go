@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCondition struct {
+	Form bool
+	V2   string
+}
+
+// runCondition runs pipes and returns names of executed steps
+func runCondition(t *testing.T, pipes PipeGroup, instance mockCondition) []string {
+	var executed []string
+
+	h, err := New(pipes, instance, converterMock, WithHooks(&conditionHook{executed: &executed}))
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+
+	return executed
+}
+
+// conditionHook records names of executed pipes
+type conditionHook struct {
+	NopHook
+
+	executed *[]string
+}
+
+func (h *conditionHook) AfterPipe(ctx context.Context, pipe Step, result Result) {
+	*h.executed = append(*h.executed, pipe.Name)
+}
+
+var isForm Predicate = func(v reflect.Value, args ...interface{}) bool {
+	return v.Interface().(mockCondition).Form
+}
+
+func Test_Handler_If_ExpectThenOrElseExecuted(t *testing.T) {
+	pipes := PipeGroup{
+		If(isForm, PipeGroup{Named("BindForm", nopPipe)}, PipeGroup{Named("BindJSON", nopPipe)}),
+		Named("CallAction", nopPipe),
+	}
+
+	assert.Equal(t, []string{"BindForm", "CallAction"}, runCondition(t, pipes, mockCondition{Form: true}))
+	assert.Equal(t, []string{"BindJSON", "CallAction"}, runCondition(t, pipes, mockCondition{}))
+}
+
+func Test_Handler_If_NoElse_ExpectSkipped(t *testing.T) {
+	pipes := PipeGroup{
+		If(isForm, PipeGroup{Named("BindForm", nopPipe)}, nil),
+		Named("CallAction", nopPipe),
+	}
+
+	assert.Equal(t, []string{"CallAction"}, runCondition(t, pipes, mockCondition{}))
+}
+
+func Test_Handler_Switch_ExpectFirstMatchingCaseExecuted(t *testing.T) {
+	group := Switch(
+		Case{Predicate: FieldSet("V2"), Pipes: PipeGroup{Named("V2", nopPipe)}},
+		Case{Predicate: isForm, Pipes: PipeGroup{Named("Form", nopPipe)}},
+	)
+
+	pipes := PipeGroup{group, Named("CallAction", nopPipe)}
+
+	assert.Equal(t, []string{"V2", "CallAction"}, runCondition(t, pipes, mockCondition{Form: true, V2: "v2"}))
+	assert.Equal(t, []string{"Form", "CallAction"}, runCondition(t, pipes, mockCondition{Form: true}))
+	assert.Equal(t, []string{"CallAction"}, runCondition(t, pipes, mockCondition{}))
+
+	group.Default = PipeGroup{Named("Default", nopPipe)}
+
+	assert.Equal(t, []string{"Default", "CallAction"}, runCondition(t, PipeGroup{group, Named("CallAction", nopPipe)}, mockCondition{}))
+}
+
+func Test_Handler_If_PipeAborted_ExpectAbortSemanticsOfGroup(t *testing.T) {
+	var abort Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, nil
+	}
+
+	// pipe of array skips to the next element, pipe placed directly in group stops execution
+	skipping := PipeGroup{If(isForm, PipeGroup{PipeArray{abort, Named("Skipped", nopPipe)}}, nil), Named("CallAction", nopPipe)}
+	stopping := PipeGroup{If(isForm, PipeGroup{abort, Named("Skipped", nopPipe)}, nil), Named("CallAction", nopPipe)}
+
+	assert.NotContains(t, runCondition(t, skipping, mockCondition{Form: true}), "Skipped")
+	assert.Contains(t, runCondition(t, skipping, mockCondition{Form: true}), "CallAction")
+	assert.NotContains(t, runCondition(t, stopping, mockCondition{Form: true}), "CallAction")
+}
+
+func Test_Compile_Switch_ExpectJumpsToChosenGroupAndExit(t *testing.T) {
+	plan := compile(PipeGroup{Switch(
+		Case{Predicate: isForm, Pipes: PipeGroup{nopPipe}},
+		Case{Predicate: isForm, Pipes: PipeGroup{nopPipe}},
+	)}, true)
+
+	var ops []opcode
+	for _, i := range plan {
+		ops = append(ops, i.op)
+	}
+
+	assert.Equal(t, []opcode{
+		opEnter, opEnter, opSwitch,
+		opEnter, opPipe, opExit, opJump,
+		opEnter, opPipe, opExit, opJump,
+		opExit, opExit,
+	}, ops)
+
+	assert.Equal(t, []int{3, 7, 11}, plan[2].targets)
+	assert.Equal(t, 11, plan[6].jump)
+	assert.Equal(t, 11, plan[10].jump)
+}
+
+func Test_New_ConditionInvalid_ExpectError(t *testing.T) {
+	cases := map[string]struct {
+		group interface{}
+		err   error
+	}{
+		"if without predicate":   {If(nil, PipeGroup{nopPipe}, nil), ErrorPredicateNil},
+		"if without then":        {If(isForm, nil, nil), ErrorPipeGroupEmpty},
+		"empty else":             {If(isForm, PipeGroup{nopPipe}, PipeGroup{}), ErrorPipeGroupEmpty},
+		"switch without cases":   {Switch(), ErrorPipeGroupEmpty},
+		"case without predicate": {Switch(Case{Pipes: PipeGroup{nopPipe}}), ErrorPredicateNil},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(PipeGroup{c.group}, mockCondition{}, converterMock)
+
+			assert.True(t, errors.Is(err, c.err), err)
+		})
+	}
+}
+
+func Test_Dump_Switch_ExpectCasesAndSkipEdges(t *testing.T) {
+	pipes := PipeGroup{
+		Switch(
+			Case{Predicate: NamedPredicate("IsForm", isForm), Pipes: PipeGroup{PipeArray{Named("BindForm", nopPipe)}}},
+			Case{Predicate: FieldSet("V2"), Pipes: PipeGroup{PipeArray{Named("BindV2", nopPipe)}}},
+		),
+		PipeArray{Named("CallAction", nopPipe)},
+	}
+
+	assert.Equal(t, `group
+  [0] switch
+    [0][0] case IsForm
+      [0][0][0] array
+        [0][0][0][0] BindForm (abort: skip to [1][0])
+    [0][1] case FieldSet(V2)
+      [0][1][0] array
+        [0][1][0][0] BindV2 (abort: skip to [1][0])
+  [1] array
+    [1][0] CallAction (abort: skip to end)
+`, Dump(pipes))
+
+	// start is connected to CallAction, because no case may match
+	assert.Contains(t, DumpMermaid(pipes), `  start --> p_0_0_0_0
+  start --> p_0_1_0_0
+  p_0_0_0_0 --> p_1_0
+  p_0_1_0_0 --> p_1_0
+  start --> p_1_0
+`)
+}
+
+func Test_Handler_WithRecovery_PredicatePanics_ExpectPredicateInPipeError(t *testing.T) {
+	panicking := NamedPredicate("IsJSON", func(v reflect.Value, args ...interface{}) bool {
+		panic("predicate panicked")
+	})
+
+	cases := map[string]struct {
+		pipes PipeGroup
+		path  Path
+	}{
+		"if":     {pipes: PipeGroup{nopPipe, If(panicking, PipeGroup{nopPipe}, nil)}, path: Path{1, 0}},
+		"switch": {pipes: PipeGroup{Switch(Case{Predicate: isForm, Pipes: PipeGroup{nopPipe}}, Case{Predicate: panicking, Pipes: PipeGroup{nopPipe}})}, path: Path{0, 1}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(c.pipes, mockCondition{}, converterMock, WithRecovery())
+			assert.NoError(t, err)
+
+			err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+			var pipeErr *PipeError
+
+			assert.ErrorAs(t, err, &pipeErr)
+			assert.Equal(t, "predicate IsJSON", pipeErr.Pipe)
+			assert.Equal(t, c.path, pipeErr.Path)
+		})
+	}
+}
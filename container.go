@@ -0,0 +1,406 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Scope represents lifetime of service registered in Container
+type Scope int
+
+const (
+	// Singleton service is created once and shared by all requests
+	Singleton Scope = iota
+
+	// PerRequest service is created once for each request
+	PerRequest
+
+	// Transient service is created each time it's resolved
+	Transient
+)
+
+func (s Scope) String() string {
+	switch s {
+	case Singleton:
+		return "singleton"
+	case PerRequest:
+		return "per-request"
+	case Transient:
+		return "transient"
+	}
+
+	return "Scope(" + fmt.Sprint(int(s)) + ")"
+}
+
+// RequestScoper may be implemented by Resolver what keeps per-request
+// dependencies, handler calls BeginRequest before constructing instance
+type RequestScoper interface {
+	BeginRequest(ctx context.Context) context.Context
+}
+
+var (
+	_ Resolver      = (*Container)(nil)
+	_ RequestScoper = (*Container)(nil)
+)
+
+// Container is a dependency injection registry.
+// Services are registered by type or by name as values or factories
+// and are resolved for constructors and Inject option
+//
+// Example:
+//
+//	c := handler.NewContainer()
+//	c.Register(db)
+//	c.RegisterNamed("articles", &sqlArticlesRepo{})
+//	c.Factory(handler.PerRequest, func(ctx context.Context, db *sql.DB) (*sql.Tx, error) {
+//		return db.BeginTx(ctx, nil)
+//	})
+type Container struct {
+	mu sync.RWMutex
+
+	byType map[reflect.Type]*service
+	byName map[string]*service
+}
+
+// service represents registered value or factory
+type service struct {
+	name  string
+	t     reflect.Type
+	scope Scope
+
+	// value of service registered as value
+	value reflect.Value
+
+	// factory func and its parameter types, if service registered as factory
+	factory reflect.Value
+	params  []reflect.Type
+
+	// singleton created by factory, creation is retried while factory fails
+	mu        sync.Mutex
+	created   atomic.Bool
+	singleton reflect.Value
+}
+
+func (s *service) String() string {
+	if s.name != "" {
+		return fmt.Sprintf("%q", s.name)
+	}
+
+	return s.t.String()
+}
+
+// NewContainer creates empty Container
+func NewContainer() *Container {
+	return &Container{
+		byType: map[reflect.Type]*service{},
+		byName: map[string]*service{},
+	}
+}
+
+// Register registers singleton value by its dynamic type.
+// Use RegisterAs to register value by interface type
+func (c *Container) Register(value interface{}) error {
+	if value == nil {
+		return ErrorServiceNil
+	}
+
+	return c.add(&service{t: reflect.TypeOf(value), value: reflect.ValueOf(value)})
+}
+
+// RegisterAs registers singleton value by type T
+//
+// Example:
+//
+//	handler.RegisterAs[ArticlesRepo](c, &sqlArticlesRepo{db: db})
+func RegisterAs[T any](c *Container, value T) error {
+	return c.add(&service{t: reflect.TypeOf((*T)(nil)).Elem(), value: reflect.ValueOf(&value).Elem()})
+}
+
+// RegisterNamed registers singleton value by name
+func (c *Container) RegisterNamed(name string, value interface{}) error {
+	if value == nil {
+		return ErrorServiceNil
+	}
+
+	return c.add(&service{name: name, t: reflect.TypeOf(value), value: reflect.ValueOf(value)})
+}
+
+// Factory registers factory of service by type of its first return value.
+//
+// Factory func may accept context.Context and services registered by type,
+// and may return error as second value. Singleton factories get
+// context.Background(), others get context of request
+func (c *Container) Factory(scope Scope, factory interface{}) error {
+	return c.addFactory("", scope, factory)
+}
+
+// NamedFactory registers factory of service by name
+func (c *Container) NamedFactory(name string, scope Scope, factory interface{}) error {
+	return c.addFactory(name, scope, factory)
+}
+
+func (c *Container) addFactory(name string, scope Scope, factory interface{}) error {
+	v := reflect.ValueOf(factory)
+
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ErrorServiceFactory
+	}
+
+	t := v.Type()
+
+	if t.IsVariadic() || t.NumOut() == 0 || t.NumOut() > 2 || t.NumOut() == 2 && t.Out(1) != errorType {
+		return ErrorServiceFactory
+	}
+
+	s := &service{
+		name:    name,
+		t:       t.Out(0),
+		scope:   scope,
+		factory: v,
+	}
+
+	for i := 0; i < t.NumIn(); i++ {
+		s.params = append(s.params, t.In(i))
+	}
+
+	return c.add(s)
+}
+
+func (c *Container) add(s *service) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s.name != "" {
+		if _, exists := c.byName[s.name]; exists {
+			return fmt.Errorf("%w %s", ErrorServiceDuplicate, s)
+		}
+
+		c.byName[s.name] = s
+
+		return nil
+	}
+
+	if _, exists := c.byType[s.t]; exists {
+		return fmt.Errorf("%w %s", ErrorServiceDuplicate, s)
+	}
+
+	c.byType[s.t] = s
+
+	return nil
+}
+
+// Has reports whether service of type t and all its dependencies are registered
+func (c *Container) Has(t reflect.Type) bool {
+	return c.Check(t) == nil
+}
+
+// Check returns error if service of type t or any of its dependencies
+// is not registered, there is dependency cycle or singleton depends
+// on per-request service
+func (c *Container) Check(t reflect.Type) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, ok := c.byType[t]
+	if !ok {
+		return fmt.Errorf("%w %s", ErrorServiceUnresolved, t)
+	}
+
+	return c.check(s, map[*service]bool{})
+}
+
+// CheckNamed is Check for service registered by name
+func (c *Container) CheckNamed(name string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, ok := c.byName[name]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrorServiceUnresolved, name)
+	}
+
+	return c.check(s, map[*service]bool{})
+}
+
+func (c *Container) check(s *service, visiting map[*service]bool) error {
+	if visiting[s] {
+		return fmt.Errorf("%w %s", ErrorServiceCycle, s)
+	}
+
+	visiting[s] = true
+	defer delete(visiting, s)
+
+	for _, param := range s.params {
+		if param == contextType {
+			continue
+		}
+
+		dependency, ok := c.byType[param]
+		if !ok {
+			return fmt.Errorf("%w %s required by %s", ErrorServiceUnresolved, param, s)
+		}
+
+		if s.scope == Singleton && dependency.scope != Singleton {
+			return fmt.Errorf("%w: singleton %s depends on %s %s", ErrorServiceScope, s, dependency.scope, dependency)
+		}
+
+		if err := c.check(dependency, visiting); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// namedType returns type of service registered by name
+func (c *Container) namedType(name string) reflect.Type {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.byName[name].t
+}
+
+// Resolve returns service of type t
+func (c *Container) Resolve(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+	c.mu.RLock()
+	s, ok := c.byType[t]
+	c.mu.RUnlock()
+
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w %s", ErrorServiceUnresolved, t)
+	}
+
+	return c.resolve(ctx, s)
+}
+
+// ResolveNamed returns service registered by name
+func (c *Container) ResolveNamed(ctx context.Context, name string) (reflect.Value, error) {
+	c.mu.RLock()
+	s, ok := c.byName[name]
+	c.mu.RUnlock()
+
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w %q", ErrorServiceUnresolved, name)
+	}
+
+	return c.resolve(ctx, s)
+}
+
+func (c *Container) resolve(ctx context.Context, s *service) (reflect.Value, error) {
+	if s.value.IsValid() {
+		return s.value, nil
+	}
+
+	switch s.scope {
+	case Singleton:
+		return c.singleton(s)
+	case PerRequest:
+		scope, ok := ctx.Value(requestScopeKey{}).(*requestScope)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%w %s", ErrorServiceNoRequestScope, s)
+		}
+
+		return scope.resolve(s, func() (reflect.Value, error) {
+			return c.create(ctx, s)
+		})
+	}
+
+	return c.create(ctx, s)
+}
+
+// singleton creates singleton on the first successful call of factory
+func (c *Container) singleton(s *service) (reflect.Value, error) {
+	if s.created.Load() {
+		return s.singleton, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.created.Load() {
+		return s.singleton, nil
+	}
+
+	value, err := c.create(context.Background(), s)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	s.singleton = value
+	s.created.Store(true)
+
+	return value, nil
+}
+
+// create calls factory of service
+func (c *Container) create(ctx context.Context, s *service) (reflect.Value, error) {
+	in := make([]reflect.Value, len(s.params))
+
+	for i, param := range s.params {
+		if param == contextType {
+			in[i] = reflect.ValueOf(&ctx).Elem()
+
+			continue
+		}
+
+		value, err := c.Resolve(ctx, param)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		in[i] = value
+	}
+
+	out := s.factory.Call(in)
+
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, out[1].Interface().(error)
+	}
+
+	return out[0], nil
+}
+
+// BeginRequest returns context what holds per-request services
+func (c *Container) BeginRequest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestScopeKey{}, &requestScope{
+		values: map[*service]reflect.Value{},
+	})
+}
+
+type requestScopeKey struct{}
+
+// requestScope holds per-request services
+type requestScope struct {
+	mu     sync.Mutex
+	values map[*service]reflect.Value
+}
+
+func (r *requestScope) resolve(s *service, create func() (reflect.Value, error)) (reflect.Value, error) {
+	r.mu.Lock()
+	value, ok := r.values[s]
+	r.mu.Unlock()
+
+	if ok {
+		return value, nil
+	}
+
+	// creating without lock, because factory may resolve other per-request services
+	value, err := create()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.values[s]; ok {
+		return existing, nil
+	}
+
+	r.values[s] = value
+
+	return value, nil
+}
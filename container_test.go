@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	mockTx struct {
+		ID int
+	}
+
+	mockCache struct {
+		Name string
+	}
+
+	mockInjectedHandler struct {
+		Services struct {
+			Repo    mockRepo
+			Tx      *mockTx
+			Cache   *mockCache `inject:"articles-cache"`
+			Skipped *mockCache `inject:"-"`
+		}
+	}
+)
+
+var (
+	mockRepoType = reflect.TypeOf((*mockRepo)(nil)).Elem()
+	mockTxType   = reflect.TypeOf(&mockTx{})
+)
+
+func newMockContainer(t *testing.T, txScope Scope) (*Container, *int) {
+	c := NewContainer()
+	created := new(int)
+
+	assert.NoError(t, RegisterAs[mockRepo](c, mockRepoImpl{}))
+	assert.NoError(t, c.RegisterNamed("articles-cache", &mockCache{Name: "articles"}))
+	assert.NoError(t, c.Factory(txScope, func(ctx context.Context, repo mockRepo) (*mockTx, error) {
+		*created++
+
+		return &mockTx{ID: *created}, nil
+	}))
+
+	return c, created
+}
+
+func Test_Container_Scopes_ExpectServiceCreatedOncePerScope(t *testing.T) {
+	cases := []struct {
+		scope    Scope
+		expected []int
+	}{
+		{Singleton, []int{1, 1, 1}},
+		{PerRequest, []int{1, 1, 2}},
+		{Transient, []int{1, 2, 3}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.scope.String(), func(t *testing.T) {
+			c, _ := newMockContainer(t, tc.scope)
+
+			first := c.BeginRequest(context.Background())
+			second := c.BeginRequest(context.Background())
+
+			var ids []int
+
+			for _, ctx := range []context.Context{first, first, second} {
+				tx, err := c.Resolve(ctx, mockTxType)
+				assert.NoError(t, err)
+
+				ids = append(ids, tx.Interface().(*mockTx).ID)
+			}
+
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func Test_Container_SingletonFactoryFails_ExpectCreationRetried(t *testing.T) {
+	c := NewContainer()
+	calls := 0
+
+	assert.NoError(t, c.Factory(Singleton, func() (*mockTx, error) {
+		calls++
+
+		if calls == 1 {
+			return nil, errors.New("connection refused")
+		}
+
+		return &mockTx{ID: calls}, nil
+	}))
+
+	_, err := c.Resolve(context.Background(), mockTxType)
+	assert.Error(t, err)
+
+	for i := 0; i < 2; i++ {
+		tx, err := c.Resolve(context.Background(), mockTxType)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tx.Interface().(*mockTx).ID)
+	}
+
+	assert.Equal(t, 2, calls)
+}
+
+func Test_Container_PerRequestWithoutScope_ExpectError(t *testing.T) {
+	c, _ := newMockContainer(t, PerRequest)
+
+	_, err := c.Resolve(context.Background(), mockTxType)
+
+	assert.True(t, errors.Is(err, ErrorServiceNoRequestScope))
+}
+
+func Test_Container_InvalidRegistrations_ExpectErrors(t *testing.T) {
+	c, _ := newMockContainer(t, Singleton)
+
+	assert.True(t, errors.Is(RegisterAs[mockRepo](c, mockRepoImpl{}), ErrorServiceDuplicate))
+	assert.True(t, errors.Is(c.RegisterNamed("articles-cache", &mockCache{}), ErrorServiceDuplicate))
+	assert.True(t, errors.Is(c.Register(nil), ErrorServiceNil))
+	assert.True(t, errors.Is(c.Factory(Singleton, "not func"), ErrorServiceFactory))
+	assert.True(t, errors.Is(c.Factory(Singleton, func() (*mockCache, int) { return nil, 0 }), ErrorServiceFactory))
+}
+
+func Test_Container_Check_ExpectDependencyErrors(t *testing.T) {
+	c := NewContainer()
+
+	assert.NoError(t, c.Factory(Singleton, func(tx *mockTx) *mockCache { return nil }))
+	assert.True(t, errors.Is(c.Check(reflect.TypeOf(&mockCache{})), ErrorServiceUnresolved))
+
+	c = NewContainer()
+
+	assert.NoError(t, c.Factory(PerRequest, func(tx *mockTx) *mockCache { return nil }))
+	assert.NoError(t, c.Factory(PerRequest, func(cache *mockCache) *mockTx { return nil }))
+	assert.True(t, errors.Is(c.Check(mockTxType), ErrorServiceCycle))
+
+	c = NewContainer()
+
+	assert.NoError(t, c.Factory(PerRequest, func() *mockTx { return nil }))
+	assert.NoError(t, c.Factory(Singleton, func(tx *mockTx) *mockCache { return nil }))
+	assert.True(t, errors.Is(c.Check(reflect.TypeOf(&mockCache{})), ErrorServiceScope))
+}
+
+func Test_Handler_Inject_ExpectServicesFieldFilled(t *testing.T) {
+	c, _ := newMockContainer(t, PerRequest)
+
+	var instance reflect.Value
+
+	h, err := New(PipeGroup{captureInstance(&instance)}, &mockInjectedHandler{}, converterMock, Inject(c, ""))
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+	assert.NoError(t, err)
+
+	services := instance.Interface().(*mockInjectedHandler).Services
+
+	assert.Equal(t, mockRepoImpl{}, services.Repo)
+	assert.Equal(t, &mockTx{ID: 1}, services.Tx)
+	assert.Equal(t, &mockCache{Name: "articles"}, services.Cache)
+	assert.Nil(t, services.Skipped)
+}
+
+type mockPointerServicesHandler struct {
+	Services *struct {
+		Tx   *mockTx
+		Name string `inject:"-"`
+	}
+}
+
+func Test_Handler_InjectPointerServices_ConcurrentRequests_ExpectServicesNotShared(t *testing.T) {
+	c := NewContainer()
+	created := int32(0)
+
+	assert.NoError(t, c.Factory(PerRequest, func() *mockTx {
+		return &mockTx{ID: int(atomic.AddInt32(&created, 1))}
+	}))
+
+	prototype := &mockPointerServicesHandler{}
+	prototype.Services = &struct {
+		Tx   *mockTx
+		Name string `inject:"-"`
+	}{Name: "prototype"}
+
+	instances := make([]*mockPointerServicesHandler, 2)
+
+	var wg sync.WaitGroup
+
+	for n := range instances {
+		var pipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+			instances[n] = v.Interface().(*mockPointerServicesHandler)
+
+			return ContinuePipeGroup(v), nil
+		}
+
+		h, err := New(PipeGroup{pipe}, prototype, converterMock, Inject(c, ""))
+		assert.NoError(t, err)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.NotSame(t, instances[0].Services, instances[1].Services)
+	assert.NotEqual(t, instances[0].Services.Tx.ID, instances[1].Services.Tx.ID)
+	assert.Equal(t, "prototype", instances[0].Services.Name, "fields of prototype are copied")
+	assert.Nil(t, prototype.Services.Tx, "prototype is not changed")
+}
+
+func Test_New_InjectCtorReturnsStruct_ExpectServicesInjectedToAddressableCopy(t *testing.T) {
+	c, _ := newMockContainer(t, PerRequest)
+
+	var instance reflect.Value
+
+	h, err := New(PipeGroup{captureInstance(&instance)}, func() mockInjectedHandler {
+		return mockInjectedHandler{}
+	}, converterMock, Inject(c, ""))
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+	assert.NoError(t, err)
+
+	assert.True(t, instance.CanAddr())
+	assert.Equal(t, &mockTx{ID: 1}, instance.Interface().(mockInjectedHandler).Services.Tx)
+}
+
+func Test_New_InjectUnresolvedService_ExpectError(t *testing.T) {
+	c := NewContainer()
+	assert.NoError(t, RegisterAs[mockRepo](c, mockRepoImpl{}))
+
+	_, err := New(mockPipes, &mockInjectedHandler{}, converterMock, Inject(c, ""))
+
+	assert.True(t, errors.Is(err, ErrorServiceUnresolved))
+	assert.Contains(t, err.Error(), "Services.Tx")
+
+	_, err = New(mockPipes, &mockInjectedHandler{}, converterMock, Inject(c, "Missing"))
+
+	assert.True(t, errors.Is(err, ErrorInjectField))
+}
+
+func Test_New_CtorWithContainer_ExpectResolved(t *testing.T) {
+	c, _ := newMockContainer(t, PerRequest)
+
+	var instance reflect.Value
+
+	h, err := New(PipeGroup{captureInstance(&instance)}, func(repo mockRepo, tx *mockTx) *mockStruct {
+		return &mockStruct{Field1: repo.Name(), Field2: "tx"}
+	}, converterMock, WithDependencies(c))
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &mockStruct{Field1: "repo", Field2: "tx"}, instance.Interface())
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// ContextPipe represents execution pipe what receives context of execution.
+//
+// Context is done when request is cancelled by client or
+// timeout of TimeoutGroup is exceeded
+//
+// Example:
+//
+//	var LoadArticles handler.ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+//		articles, err := db.QueryContext(ctx, "SELECT * FROM articles")
+//		...
+//	}
+type ContextPipe func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error)
+
+// TimeoutGroup represents group of pipes what context is done
+// after Timeout is exceeded.
+//
+// Pipes are not interrupted, exceeding timeout is detected
+// by executor before next pipe or by ContextPipe itself
+type TimeoutGroup struct {
+	Timeout time.Duration
+	Pipes   PipeGroup
+}
+
+// Timeout creates group of pipes with timeout
+//
+// Example:
+//
+//	var ActionPipes = handler.PipeGroup{
+//		handler.PipeArray{BindRequestPipe, ValidateRequestPipe},
+//		handler.Timeout(time.Second, handler.PipeGroup{LoadArticles}),
+//	}
+func Timeout(timeout time.Duration, pipes PipeGroup) TimeoutGroup {
+	return TimeoutGroup{
+		Timeout: timeout,
+		Pipes:   pipes,
+	}
+}
+
+// ContextError is returned by generic handler when execution is aborted
+// because context is done. Err is context.Canceled or context.DeadlineExceeded
+type ContextError struct {
+	Err error
+}
+
+func (e *ContextError) Error() string {
+	return "handler: execution aborted: " + e.Err.Error()
+}
+
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
+
+// contextFromArgs returns context of first argument what is context.Context,
+// has Context() method or has Request() method
+func contextFromArgs(args ...interface{}) context.Context {
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case context.Context:
+			return arg
+		case interface{ Context() context.Context }:
+			return arg.Context()
+		case interface{ Request() *http.Request }:
+			if r := arg.Request(); r != nil {
+				return r.Context()
+			}
+		}
+	}
+
+	return context.Background()
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockContextKey struct{}
+
+func Test_Handler_ContextPipe_ExpectContextFromArgs(t *testing.T) {
+	ctx := context.WithValue(context.Background(), mockContextKey{}, "value")
+
+	var received interface{}
+
+	var pipe ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		received = ctx.Value(mockContextKey{})
+
+		return &v, nil
+	}
+
+	h, err := New(PipeGroup{pipe}, mockStruct{}, func(f GenericHandlerFunc) interface{} {
+		return f
+	})
+	assert.NoError(t, err)
+
+	err = h.Handler().(GenericHandlerFunc)(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "value", received)
+}
+
+func Test_Handler_WithContext_ExpectCustomExtractorUsed(t *testing.T) {
+	ctx := context.WithValue(context.Background(), mockContextKey{}, "custom")
+
+	var received interface{}
+
+	var pipe ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		received = ctx.Value(mockContextKey{})
+
+		return &v, nil
+	}
+
+	h, err := New(PipeGroup{pipe}, mockStruct{}, converterMock, WithContext(func(args ...interface{}) context.Context {
+		return ctx
+	}))
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", received)
+}
+
+func Test_Handler_ContextCancelled_ExpectContextErrorAndNextPipeNotExecuted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var cancelPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		cancel()
+
+		return &v, nil
+	}
+
+	executed := false
+	var pipeWhatHaveToNotExecuteAfter Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		executed = true
+
+		return &v, nil
+	}
+
+	h, err := New(PipeGroup{[]Pipe{cancelPipe, pipeWhatHaveToNotExecuteAfter}}, mockStruct{}, func(f GenericHandlerFunc) interface{} {
+		return f
+	})
+	assert.NoError(t, err)
+
+	err = h.Handler().(GenericHandlerFunc)(ctx)
+
+	var contextError *ContextError
+
+	assert.True(t, errors.As(err, &contextError))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, executed)
+}
+
+func Test_Handler_TimeoutGroup_ExpectDeadlineExceeded(t *testing.T) {
+	var slowPipe ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		<-ctx.Done()
+
+		return &v, nil
+	}
+
+	executed := false
+	var pipeWhatHaveToNotExecuteAfter Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		executed = true
+
+		return &v, nil
+	}
+
+	pipes := PipeGroup{
+		Timeout(time.Millisecond, PipeGroup{slowPipe, pipeWhatHaveToNotExecuteAfter}),
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, executed)
+}
+
+func Test_Handler_TimeoutGroup_ExpectContextRestoredAfterGroup(t *testing.T) {
+	var deadlines []bool
+
+	var pipe ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		_, ok := ctx.Deadline()
+		deadlines = append(deadlines, ok)
+
+		return &v, nil
+	}
+
+	pipes := PipeGroup{
+		Timeout(time.Minute, PipeGroup{pipe}),
+		pipe,
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, deadlines)
+}
+
+func Test_ContextFromArgs_HttpRequest_ExpectRequestContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), mockContextKey{}, "request")
+	r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+
+	assert.Equal(t, "request", contextFromArgs(httptest.NewRecorder(), r).Value(mockContextKey{}))
+	assert.Equal(t, context.Background(), contextFromArgs(&mockContext{}))
+}
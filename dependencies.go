@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	argsType    = reflect.TypeOf([]interface{}(nil))
+)
+
+// Resolver resolves dependencies of handler constructor by type
+type Resolver interface {
+	// Has reports whether dependency of type t can be resolved,
+	// it's checked by New
+	Has(t reflect.Type) bool
+
+	// Resolve returns dependency of type t for request
+	Resolve(ctx context.Context, t reflect.Type) (reflect.Value, error)
+}
+
+// WithDependencies sets resolver of constructor dependencies.
+//
+// Constructor func may accept context.Context of request, arguments passed
+// to generic handler as last variadic ...interface{} parameter and
+// any types resolved by resolver, and may return error as second value
+//
+// Example:
+//
+//	deps := handler.NewDependencies(db, logger)
+//
+//	handler.New(pipes, func(ctx context.Context, db *sql.DB) (*CreateArticle, error) {
+//		tx, err := db.BeginTx(ctx, nil)
+//		if err != nil {
+//			return nil, err
+//		}
+//
+//		return &CreateArticle{Tx: tx}, nil
+//	}, converter, handler.WithDependencies(deps))
+func WithDependencies(resolver Resolver) Option {
+	return func(h *handler) {
+		h.resolver = resolver
+	}
+}
+
+var _ Resolver = Dependencies(nil)
+
+// Dependencies is a Resolver what holds values by their types
+type Dependencies map[reflect.Type]reflect.Value
+
+// NewDependencies creates Dependencies what resolve values by their dynamic types.
+// Use Provide to register value by interface type
+func NewDependencies(values ...interface{}) Dependencies {
+	d := make(Dependencies, len(values))
+
+	for _, value := range values {
+		d[reflect.TypeOf(value)] = reflect.ValueOf(value)
+	}
+
+	return d
+}
+
+// Provide registers value in dependencies by type T
+//
+// Example:
+//
+//	handler.Provide[ArticlesRepo](deps, &sqlArticlesRepo{db: db})
+func Provide[T any](d Dependencies, value T) {
+	d[reflect.TypeOf((*T)(nil)).Elem()] = reflect.ValueOf(&value).Elem()
+}
+
+func (d Dependencies) Has(t reflect.Type) bool {
+	_, ok := d[t]
+
+	return ok
+}
+
+func (d Dependencies) Resolve(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+	v, ok := d[t]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w %s", ErrorTCtorUnresolvedDependency, t)
+	}
+
+	return v, nil
+}
+
+// ctorParam returns value of constructor parameter for request
+type ctorParam func(ctx context.Context, args []interface{}) (reflect.Value, error)
+
+// ctorParams returns getters of constructor parameters
+func ctorParams(t reflect.Type, resolver Resolver) ([]ctorParam, error) {
+	params := make([]ctorParam, 0, t.NumIn())
+
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+
+		switch {
+		case in == contextType:
+			params = append(params, func(ctx context.Context, args []interface{}) (reflect.Value, error) {
+				return reflect.ValueOf(&ctx).Elem(), nil
+			})
+		case in == argsType && t.IsVariadic() && i == t.NumIn()-1:
+			params = append(params, func(ctx context.Context, args []interface{}) (reflect.Value, error) {
+				return reflect.ValueOf(args), nil
+			})
+		case resolver == nil:
+			return nil, ErrorTCtorFuncHaveArguments
+		case !resolver.Has(in):
+			return nil, fmt.Errorf("%w %s", ErrorTCtorUnresolvedDependency, in)
+		default:
+			params = append(params, func(ctx context.Context, args []interface{}) (reflect.Value, error) {
+				return resolver.Resolve(ctx, in)
+			})
+		}
+	}
+
+	return params, nil
+}
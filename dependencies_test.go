@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRepo interface {
+	Name() string
+}
+
+type mockRepoImpl struct{}
+
+func (mockRepoImpl) Name() string {
+	return "repo"
+}
+
+func captureInstance(instance *reflect.Value) Pipe {
+	return func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		*instance = v
+
+		return &v, nil
+	}
+}
+
+func Test_New_CtorWithContextAndArgs_ExpectCalledWithRequest(t *testing.T) {
+	ctx := context.WithValue(context.Background(), mockContextKey{}, "request")
+
+	var instance reflect.Value
+
+	h, err := New(PipeGroup{captureInstance(&instance)}, func(ctx context.Context, args ...interface{}) (*mockStruct, error) {
+		return &mockStruct{
+			Field1: ctx.Value(mockContextKey{}).(string),
+			Field2: args[1].(string),
+		}, nil
+	}, func(f GenericHandlerFunc) interface{} {
+		return f
+	})
+	assert.NoError(t, err)
+
+	err = h.Handler().(GenericHandlerFunc)(ctx, "arg")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &mockStruct{Field1: "request", Field2: "arg"}, instance.Interface())
+}
+
+func Test_New_CtorReturnsError_ExpectErrorFromHandlerAndPipesNotExecuted(t *testing.T) {
+	mockError := errors.New("can't begin transaction")
+
+	executed := false
+	var pipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		executed = true
+
+		return &v, nil
+	}
+
+	h, err := New(PipeGroup{pipe}, func() (mockStruct, error) {
+		return mockStruct{}, mockError
+	}, converterMock)
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.Equal(t, mockError, err)
+	assert.False(t, executed)
+}
+
+func Test_New_CtorWithDependencies_ExpectResolved(t *testing.T) {
+	deps := NewDependencies(&mockServices{Name: "services"})
+	Provide[mockRepo](deps, mockRepoImpl{})
+
+	var instance reflect.Value
+
+	h, err := New(PipeGroup{captureInstance(&instance)}, func(services *mockServices, repo mockRepo) *mockStruct {
+		return &mockStruct{Field1: services.Name, Field2: repo.Name()}
+	}, converterMock, WithDependencies(deps))
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &mockStruct{Field1: "services", Field2: "repo"}, instance.Interface())
+}
+
+func Test_New_CtorWithUnresolvedDependency_ExpectError(t *testing.T) {
+	_, err := New(mockPipes, func(repo mockRepo) *mockStruct {
+		return &mockStruct{}
+	}, converterMock, WithDependencies(NewDependencies(&mockServices{})))
+
+	assert.True(t, errors.Is(err, ErrorTCtorUnresolvedDependency))
+	assert.Contains(t, err.Error(), "handler.mockRepo")
+}
+
+func Test_New_CtorSecondReturnNotError_ExpectError(t *testing.T) {
+	_, err := New(mockPipes, func() (*mockStruct, int) {
+		return nil, 0
+	}, converterMock)
+
+	assert.Equal(t, ErrorTCtorFuncMoreThanOneReturnType, err)
+}
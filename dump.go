@@ -0,0 +1,497 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Dump returns PipeGroup tree as indented text with paths and names of pipes.
+// Each pipe is annotated with what happens when it returns AbortPipeGroup:
+// pipe placed directly in group stops execution of handler or of its branch of ParallelGroup,
+// pipe placed in []Pipe skips to the next pipe after the array
+//
+// Example output:
+//
+//	group
+//	  [0] BindRequest [http]: binds request body to Request field (abort: stop)
+//	  [1] array
+//	    [1][0] CallAction (abort: skip to [2][0])
+//	    [1][1] Render (abort: skip to [2][0])
+//	  [2] group
+//	    [2][0] Audit (abort: stop)
+func Dump(pipes PipeGroup) string {
+	var b strings.Builder
+
+	tree := newPipeTree(pipes)
+
+	tree.walk(func(node *treeNode) {
+		b.WriteString(strings.Repeat("  ", len(node.path)))
+
+		if len(node.path) > 0 {
+			b.WriteString(node.path.String() + " ")
+		}
+
+		b.WriteString(node.label)
+
+		if node.index >= 0 {
+			b.WriteString(" (abort: " + tree.abortText(node) + ")")
+		}
+
+		b.WriteString("\n")
+	}, nil)
+
+	return b.String()
+}
+
+// DumpDOT returns PipeGroup tree as Graphviz DOT digraph.
+// Groups are clusters, solid edges show order of pipes
+// and dashed edges show where AbortPipeGroup jumps
+func DumpDOT(pipes PipeGroup) string {
+	var b strings.Builder
+
+	tree := newPipeTree(pipes)
+
+	b.WriteString("digraph handler {\n")
+	b.WriteString("  node [shape=box];\n")
+	b.WriteString("  start [shape=circle];\n")
+	b.WriteString("  end [shape=doublecircle];\n")
+
+	if tree.halts {
+		b.WriteString("  stop [shape=octagon];\n")
+	}
+
+	tree.walk(func(node *treeNode) {
+		indent := strings.Repeat("  ", len(node.path)+1)
+
+		if node.children == nil {
+			fmt.Fprintf(&b, "%s%s [label=%s];\n", indent, node.id(), dotQuote(node.label))
+
+			return
+		}
+
+		if len(node.path) > 0 {
+			fmt.Fprintf(&b, "%ssubgraph cluster_%s {\n", indent, node.id())
+			fmt.Fprintf(&b, "%s  label=%s;\n", indent, dotQuote(node.label+" "+node.path.String()))
+		}
+	}, func(node *treeNode) {
+		if len(node.path) > 0 {
+			fmt.Fprintf(&b, "%s}\n", strings.Repeat("  ", len(node.path)+1))
+		}
+	})
+
+	tree.edges(func(from, to string, abort bool) {
+		if abort {
+			fmt.Fprintf(&b, "  %s -> %s [style=dashed, label=\"abort\"];\n", from, to)
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s;\n", from, to)
+		}
+	})
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// DumpMermaid returns PipeGroup tree as Mermaid flowchart.
+// Groups are subgraphs, solid edges show order of pipes
+// and dotted edges show where AbortPipeGroup jumps
+func DumpMermaid(pipes PipeGroup) string {
+	var b strings.Builder
+
+	tree := newPipeTree(pipes)
+
+	b.WriteString("flowchart TD\n")
+	b.WriteString("  start((start))\n")
+	b.WriteString("  end_((end))\n")
+
+	if tree.halts {
+		b.WriteString("  stop{{stop}}\n")
+	}
+
+	tree.walk(func(node *treeNode) {
+		indent := strings.Repeat("  ", len(node.path)+1)
+
+		if node.children == nil {
+			fmt.Fprintf(&b, "%s%s[%s]\n", indent, node.id(), mermaidQuote(node.label))
+
+			return
+		}
+
+		if len(node.path) > 0 {
+			fmt.Fprintf(&b, "%ssubgraph %s[%s]\n", indent, node.id(), mermaidQuote(node.label+" "+node.path.String()))
+		}
+	}, func(node *treeNode) {
+		if len(node.path) > 0 {
+			fmt.Fprintf(&b, "%send\n", strings.Repeat("  ", len(node.path)+1))
+		}
+	})
+
+	tree.edges(func(from, to string, abort bool) {
+		// "end" is keyword of mermaid
+		if to == "end" {
+			to = "end_"
+		}
+
+		if abort {
+			fmt.Fprintf(&b, "  %s -.->|abort| %s\n", from, to)
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", from, to)
+		}
+	})
+
+	return b.String()
+}
+
+// pipeTree is PipeGroup tree prepared for dump
+type pipeTree struct {
+	root *treeNode
+
+	// pipes in order of tree
+	pipes []*treeNode
+
+	// transitions between pipes, start, end and stop
+	transitions []transition
+
+	// whether any pipe stops execution when aborted
+	halts bool
+}
+
+// treeNode represents element of PipeGroup tree
+type treeNode struct {
+	path  Path
+	value interface{}
+	label string
+
+	// children of group, nil for pipe
+	children []*treeNode
+
+	// index of pipe in pipeTree.pipes, -1 for groups and invalid elements
+	index int
+
+	// where execution continues when pipe returns AbortPipeGroup:
+	// "stop", "end" or paths of pipes
+	aborts []string
+}
+
+// transition represents edge of graph
+type transition struct {
+	from, to string
+
+	// index of pipe what transition starts from, -1 for start
+	index int
+
+	abort bool
+}
+
+// flowExit is pipe what passes execution to the next element of tree,
+// normally or by returning AbortPipeGroup
+type flowExit struct {
+	node  *treeNode
+	abort bool
+}
+
+func newPipeTree(pipes PipeGroup) *pipeTree {
+	tree := &pipeTree{}
+
+	tree.root = tree.node(pipes, Path{})
+
+	exits := tree.flow(tree.root, []flowExit{{}}, nil)
+	tree.connect(exits, "end", "end")
+
+	// abort edges are written after order of pipes
+	sort.SliceStable(tree.transitions, func(i, j int) bool {
+		a, b := tree.transitions[i], tree.transitions[j]
+
+		if a.abort != b.abort {
+			return !a.abort
+		}
+
+		return a.abort && a.index < b.index
+	})
+
+	return tree
+}
+
+func (t *pipeTree) node(value interface{}, path Path) *treeNode {
+	node := &treeNode{path: path, value: value, label: nodeLabel(value), index: -1}
+
+	children := nodeChildren(value)
+
+	if children == nil {
+		if isPipe(value) {
+			node.index = len(t.pipes)
+
+			t.pipes = append(t.pipes, node)
+		}
+
+		return node
+	}
+
+	node.children = make([]*treeNode, len(children))
+
+	for i, child := range children {
+		node.children[i] = t.node(child, childPath(path, i))
+	}
+
+	return node
+}
+
+// flow connects entries to node and returns pipes what pass execution to the next element.
+// Aborted pipes placed directly in group are appended to halts,
+// or connected to stop when halts is nil, because they stop execution of handler
+func (t *pipeTree) flow(node *treeNode, entries []flowExit, halts *[]flowExit) []flowExit {
+	switch node.value.(type) {
+	case []Pipe, PipeArray:
+		// aborted pipe of array skips to the element after the array
+		var aborts []flowExit
+
+		for _, child := range node.children {
+			entries = t.flow(child, entries, &aborts)
+		}
+
+		return append(entries, aborts...)
+	case ParallelGroup:
+		var exits []flowExit
+
+		for _, branch := range node.children {
+			exits = append(exits, t.flow(branch, entries, nil)...)
+		}
+
+		return exits
+	case IfGroup, SwitchGroup:
+		// groups what are not chosen are skipped
+		var exits []flowExit
+
+		for _, child := range node.children {
+			exits = append(exits, t.flow(child, entries, halts)...)
+		}
+
+		if !hasFallback(node.value) {
+			exits = append(exits, entries...)
+		}
+
+		return exits
+	case Branch:
+		// aborted pipe stops its branch only
+		var aborts []flowExit
+
+		for _, child := range node.children {
+			entries = t.flow(child, entries, &aborts)
+		}
+
+		return append(entries, aborts...)
+	}
+
+	if node.children != nil {
+		for _, child := range node.children {
+			entries = t.flow(child, entries, halts)
+		}
+
+		return entries
+	}
+
+	if node.index < 0 {
+		return entries
+	}
+
+	t.connect(entries, node.id(), node.path.String())
+
+	if halts != nil {
+		*halts = append(*halts, flowExit{node: node, abort: true})
+	} else {
+		t.connect([]flowExit{{node: node, abort: true}}, "stop", "stop")
+
+		t.halts = true
+	}
+
+	return []flowExit{{node: node}}
+}
+
+// connect adds transitions from entries to node with id
+func (t *pipeTree) connect(entries []flowExit, id, text string) {
+	for _, entry := range entries {
+		if entry.node == nil {
+			t.transitions = append(t.transitions, transition{from: "start", to: id, index: -1})
+
+			continue
+		}
+
+		if entry.abort {
+			entry.node.aborts = append(entry.node.aborts, text)
+		}
+
+		t.transitions = append(t.transitions, transition{from: entry.node.id(), to: id, index: entry.node.index, abort: entry.abort})
+	}
+}
+
+// walk calls enter for each node in order of tree and exit after children of group
+func (t *pipeTree) walk(enter, exit func(node *treeNode)) {
+	var walk func(node *treeNode)
+
+	walk = func(node *treeNode) {
+		enter(node)
+
+		for _, child := range node.children {
+			walk(child)
+		}
+
+		if node.children != nil && exit != nil {
+			exit(node)
+		}
+	}
+
+	walk(t.root)
+}
+
+// edges calls edge for each transition between pipes, start, end and stop
+func (t *pipeTree) edges(edge func(from, to string, abort bool)) {
+	for _, transition := range t.transitions {
+		edge(transition.from, transition.to, transition.abort)
+	}
+}
+
+func (t *pipeTree) abortText(node *treeNode) string {
+	if len(node.aborts) == 1 && node.aborts[0] == "stop" {
+		return "stop"
+	}
+
+	return "skip to " + strings.Join(node.aborts, ", ")
+}
+
+// id returns identifier of node in graph, like p_1_0
+func (n *treeNode) id() string {
+	var b strings.Builder
+
+	if n.children == nil {
+		b.WriteString("p")
+	} else {
+		b.WriteString("g")
+	}
+
+	for _, i := range n.path {
+		fmt.Fprintf(&b, "_%d", i)
+	}
+
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// nodeLabel returns label of element of PipeGroup tree
+func nodeLabel(node interface{}) string {
+	switch node := node.(type) {
+	case PipeGroup:
+		return StepGroup.String()
+	case []Pipe, PipeArray:
+		return StepArray.String()
+	case TimeoutGroup:
+		return StepTimeout.String() + " " + node.Timeout.String()
+	case ParallelGroup:
+		if node.JoinErrors {
+			return StepParallel.String() + " (join errors)"
+		}
+
+		return StepParallel.String()
+	case IfGroup:
+		return StepIf.String() + " " + predicateLabel(node.Predicate)
+	case SwitchGroup:
+		return StepSwitch.String()
+	case conditionBranch:
+		return node.label
+	case Branch:
+		if len(node.Fields) == 0 {
+			return "branch"
+		}
+
+		return "branch owns " + strings.Join(node.Fields, ", ")
+	case nil:
+		return "nil"
+	}
+
+	if !isPipe(node) {
+		return fmt.Sprintf("unsupported %T", node)
+	}
+
+	info := InfoOf(node)
+
+	if info.Description == "" {
+		return info.String()
+	}
+
+	return info.String() + ": " + info.Description
+}
+
+// nodeChildren returns nested elements of group, nil for pipes
+func nodeChildren(node interface{}) []interface{} {
+	switch node := node.(type) {
+	case PipeGroup:
+		return append([]interface{}{}, node...)
+	case TimeoutGroup:
+		return append([]interface{}{}, node.Pipes...)
+	case ParallelGroup:
+		children := make([]interface{}, len(node.Branches))
+
+		for i, branch := range node.Branches {
+			children[i] = branch
+		}
+
+		return children
+	case Branch:
+		return append([]interface{}{}, node.Pipes...)
+	case IfGroup, SwitchGroup:
+		var children []interface{}
+
+		for _, branch := range conditionBranches(node) {
+			children = append(children, branch)
+		}
+
+		return children
+	case conditionBranch:
+		return append([]interface{}{}, node.pipes...)
+	case []Pipe:
+		children := make([]interface{}, len(node))
+
+		for i, pipe := range node {
+			children[i] = pipe
+		}
+
+		return children
+	case PipeArray:
+		return append([]interface{}{}, node...)
+	}
+
+	return nil
+}
+
+// isPipe reports whether node is pipe, named pipe or plain func with signature of pipe
+func isPipe(node interface{}) bool {
+	switch node.(type) {
+	case Pipe, ContextPipe, NamedPipe,
+		func(reflect.Value, ...interface{}) (*reflect.Value, error),
+		func(context.Context, reflect.Value, ...interface{}) (*reflect.Value, error):
+		return true
+	}
+
+	return false
+}
+
+// hasFallback reports whether IfGroup or SwitchGroup runs pipes when no predicate is true
+func hasFallback(node interface{}) bool {
+	switch node := node.(type) {
+	case IfGroup:
+		return node.Else != nil
+	case SwitchGroup:
+		return node.Default != nil
+	}
+
+	return false
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var dumpPipes = PipeGroup{
+	Named("InjectServices", nopPipe),
+	PipeArray{Named("CallAction", nopPipe, Description("calls action"), Tags("http")), Named("Render", nopPipe)},
+	Timeout(time.Second, PipeGroup{
+		PipeArray{Named("Audit", nopPipe)},
+	}),
+}
+
+func Test_Dump_ExpectIndentedTreeWithAbortTargets(t *testing.T) {
+	assert.Equal(t, `group
+  [0] InjectServices (abort: stop)
+  [1] array
+    [1][0] CallAction [http]: calls action (abort: skip to [2][0][0])
+    [1][1] Render (abort: skip to [2][0][0])
+  [2] timeout 1s
+    [2][0] array
+      [2][0][0] Audit (abort: skip to end)
+`, Dump(dumpPipes))
+}
+
+func Test_Dump_NestedArrays_ExpectAbortSkipsToNextArray(t *testing.T) {
+	pipes := PipeGroup{
+		PipeArray{Named("1", nopPipe), Named("2", nopPipe)},
+		PipeGroup{
+			PipeArray{Named("3", nopPipe)},
+			PipeGroup{
+				PipeArray{Named("4", nopPipe)},
+			},
+		},
+	}
+
+	assert.Equal(t, `group
+  [0] array
+    [0][0] 1 (abort: skip to [1][0][0])
+    [0][1] 2 (abort: skip to [1][0][0])
+  [1] group
+    [1][0] array
+      [1][0][0] 3 (abort: skip to [1][1][0][0])
+    [1][1] group
+      [1][1][0] array
+        [1][1][0][0] 4 (abort: skip to end)
+`, Dump(pipes))
+}
+
+func Test_Dump_UnnamedAndUnsupported_ExpectFuncAndTypeNames(t *testing.T) {
+	dump := Dump(PipeGroup{nopPipe, 42})
+
+	assert.Contains(t, dump, "[0] github.com/mykytanikitenko/go-handle.")
+	assert.Contains(t, dump, "[1] unsupported int\n")
+}
+
+func Test_DumpDOT_ExpectClustersAndAbortEdges(t *testing.T) {
+	assert.Equal(t, `digraph handler {
+  node [shape=box];
+  start [shape=circle];
+  end [shape=doublecircle];
+  stop [shape=octagon];
+    p_0 [label="InjectServices"];
+    subgraph cluster_g_1 {
+      label="array [1]";
+      p_1_0 [label="CallAction [http]: calls action"];
+      p_1_1 [label="Render"];
+    }
+    subgraph cluster_g_2 {
+      label="timeout 1s [2]";
+      subgraph cluster_g_2_0 {
+        label="array [2][0]";
+        p_2_0_0 [label="Audit"];
+      }
+    }
+  start -> p_0;
+  p_0 -> p_1_0;
+  p_1_0 -> p_1_1;
+  p_1_1 -> p_2_0_0;
+  p_2_0_0 -> end;
+  p_0 -> stop [style=dashed, label="abort"];
+  p_1_0 -> p_2_0_0 [style=dashed, label="abort"];
+  p_1_1 -> p_2_0_0 [style=dashed, label="abort"];
+  p_2_0_0 -> end [style=dashed, label="abort"];
+}
+`, DumpDOT(dumpPipes))
+}
+
+func Test_DumpMermaid_ExpectSubgraphsAndAbortEdges(t *testing.T) {
+	assert.Equal(t, `flowchart TD
+  start((start))
+  end_((end))
+  stop{{stop}}
+    p_0["InjectServices"]
+    subgraph g_1["array [1]"]
+      p_1_0["CallAction [http]: calls action"]
+      p_1_1["Render"]
+    end
+    subgraph g_2["timeout 1s [2]"]
+      subgraph g_2_0["array [2][0]"]
+        p_2_0_0["Audit"]
+      end
+    end
+  start --> p_0
+  p_0 --> p_1_0
+  p_1_0 --> p_1_1
+  p_1_1 --> p_2_0_0
+  p_2_0_0 --> end_
+  p_0 -.->|abort| stop
+  p_1_0 -.->|abort| p_2_0_0
+  p_1_1 -.->|abort| p_2_0_0
+  p_2_0_0 -.->|abort| end_
+`, DumpMermaid(dumpPipes))
+}
+
+var parallelDumpPipes = PipeGroup{
+	Named("Bind", nopPipe),
+	Parallel(
+		Branch{Fields: []string{"User"}, Pipes: PipeGroup{Named("LoadUser", nopPipe)}},
+		Branch{Fields: []string{"Flags"}, Pipes: PipeGroup{PipeArray{Named("LoadFlags", nopPipe)}}},
+	),
+	Named("Call", nopPipe),
+}
+
+func Test_Dump_Parallel_ExpectBranchesWithAbortToJoin(t *testing.T) {
+	assert.Equal(t, `group
+  [0] Bind (abort: stop)
+  [1] parallel
+    [1][0] branch owns User
+      [1][0][0] LoadUser (abort: skip to [2])
+    [1][1] branch owns Flags
+      [1][1][0] array
+        [1][1][0][0] LoadFlags (abort: skip to [2])
+  [2] Call (abort: stop)
+`, Dump(parallelDumpPipes))
+}
+
+func Test_DumpMermaid_Parallel_ExpectFanOutAndJoin(t *testing.T) {
+	assert.Equal(t, `flowchart TD
+  start((start))
+  end_((end))
+  stop{{stop}}
+    p_0["Bind"]
+    subgraph g_1["parallel [1]"]
+      subgraph g_1_0["branch owns User [1][0]"]
+        p_1_0_0["LoadUser"]
+      end
+      subgraph g_1_1["branch owns Flags [1][1]"]
+        subgraph g_1_1_0["array [1][1][0]"]
+          p_1_1_0_0["LoadFlags"]
+        end
+      end
+    end
+    p_2["Call"]
+  start --> p_0
+  p_0 --> p_1_0_0
+  p_0 --> p_1_1_0_0
+  p_1_0_0 --> p_2
+  p_1_1_0_0 --> p_2
+  p_2 --> end_
+  p_0 -.->|abort| stop
+  p_1_0_0 -.->|abort| p_2
+  p_1_1_0_0 -.->|abort| p_2
+  p_2 -.->|abort| stop
+`, DumpMermaid(parallelDumpPipes))
+}
@@ -10,8 +10,49 @@ var (
 	ErrorPointerNonStructType   = fmt.Errorf("handler.New: pointer to non-struct type as constructor")
 	ErrorNotInterfacebleValue   = fmt.Errorf("handler.New: not interfaceble value")
 	ErrorInvalidConstructorType = fmt.Errorf("handler.New: invalid constructor type")
+	ErrorTypeNotStruct          = fmt.Errorf("handler.New: declared type of instance is not struct")
 
 	ErrorTCtorFuncHaveArguments         = fmt.Errorf("handler.New: t ctor func have arguments")
 	ErrorTCtorFuncMoreThanOneReturnType = fmt.Errorf("handler.New: t ctor func have more than one return type")
 	ErrorTCtorFuncVoid                  = fmt.Errorf("handler.New: t ctor func doesn't return any types")
+	ErrorTCtorUnresolvedDependency      = fmt.Errorf("handler.New: t ctor func have unresolved dependency")
+
+	ErrorCloneTag             = fmt.Errorf("handler.New: invalid clone tag")
+	ErrorCloneUnexportedField = fmt.Errorf("handler.New: clone tag on unexported field")
+	ErrorCloneDeepUnsupported = fmt.Errorf("handler.New: deep copy is not supported for type")
+	ErrorPrototypeNotStruct   = fmt.Errorf("handler.Prototype: prototype is not struct")
+
+	ErrorPipeNil             = fmt.Errorf("handler.New: pipe nil")
+	ErrorPipeGroupEmpty      = fmt.Errorf("handler.New: empty pipe group")
+	ErrorUnsupportedPipeType = fmt.Errorf("handler.New: unsupported pipe type")
+	ErrorInvalidTimeout      = fmt.Errorf("handler.New: timeout of pipe group should be positive")
+	ErrorProblemWriterNil    = fmt.Errorf("handler.New: problem writer nil")
+	ErrorPredicateNil        = fmt.Errorf("handler.New: predicate nil")
+
+	ErrorParallelFieldOwned   = fmt.Errorf("handler.New: field is owned by several branches of parallel group")
+	ErrorParallelField        = fmt.Errorf("handler.New: unknown field owned by branch of parallel group")
+	ErrorParallelInstanceType = fmt.Errorf("handler.Parallel: branch replaced instance with value of other type")
+
+	ErrorServiceNil            = fmt.Errorf("handler.Container: service nil")
+	ErrorServiceFactory        = fmt.Errorf("handler.Container: factory should be func returning service and optional error")
+	ErrorServiceDuplicate      = fmt.Errorf("handler.Container: service already registered")
+	ErrorServiceUnresolved     = fmt.Errorf("handler.Container: unresolved service")
+	ErrorServiceCycle          = fmt.Errorf("handler.Container: dependency cycle at service")
+	ErrorServiceScope          = fmt.Errorf("handler.Container: invalid service scope")
+	ErrorServiceNoRequestScope = fmt.Errorf("handler.Container: no request scope for per-request service")
+
+	ErrorInjectField          = fmt.Errorf("handler.New: inject field should be struct or pointer to struct")
+	ErrorInjectUnknownType    = fmt.Errorf("handler.New: can't inject services to handler of unknown type")
+	ErrorInjectType           = fmt.Errorf("handler.New: can't inject service")
+	ErrorInjectNotAddressable = fmt.Errorf("handler.Inject: not addressable services field")
+
+	ErrorEndpointMethod = fmt.Errorf("handler.New: invalid method in endpoint tag")
+	ErrorEndpointPath   = fmt.Errorf("handler.New: path in endpoint tag should start with /")
+
+	ErrorRouteInvalid   = fmt.Errorf("handler.Router: route should have method and path")
+	ErrorRouteDuplicate = fmt.Errorf("handler.Router: duplicate route")
+	ErrorMounterNil     = fmt.Errorf("handler.Router: mounter nil")
+
+	ErrorTypedPipeContext  = fmt.Errorf("handler.TypedPipe: unexpected context type")
+	ErrorTypedPipeInstance = fmt.Errorf("handler.TypedPipe: unexpected instance type")
 )
@@ -1,6 +1,10 @@
 package action
 
+import "github.com/mykytanikitenko/go-handle"
+
 type CreateArticle struct {
+	handler.Endpoint `method:"POST" path:"/articles" summary:"Create article"`
+
 	Request struct {
 		Title string `json:"title" validate:"min=3,max=40,regexp=^[a-zA-Z]*$"`
 		Body  string `json:"body" validate:"min=10,max=40"`
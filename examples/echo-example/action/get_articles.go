@@ -1,6 +1,9 @@
 package action
 
+import "github.com/mykytanikitenko/go-handle"
+
 type GetArticles struct {
+	handler.Endpoint `method:"GET" path:"/articles" summary:"List articles"`
 }
 
 func (ctrl GetArticles) Action() (interface{}, error) {
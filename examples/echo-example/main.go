@@ -5,82 +5,43 @@ import (
 	"github.com/labstack/echo/middleware"
 	"github.com/mykytanikitenko/go-handle"
 	"github.com/mykytanikitenko/go-handle/examples/echo-example/action"
+	"github.com/mykytanikitenko/go-handle/pipes"
 	"gopkg.in/validator.v2"
 	"net/http"
-	"reflect"
 )
 
-var ActionPipes = handler.PipeGroup{
-	[]handler.Pipe{BindRequestPipe, ValidateRequestPipe},
-	[]handler.Pipe{CallActionPipe, NoActionsPipe},
-}
-
-var BindRequestPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
-	field := v.FieldByName("Request")
-
-	if !field.IsValid() {
-		return handler.AbortPipeGroup, nil
-	}
-
-	ctx := args[0].(echo.Context)
-
-	if err := ctx.Bind(field.Addr().Interface()); err != nil {
-		return handler.AbortPipeGroup, err
-	}
+// EchoAdapter adapts standard pipes to echo
+type EchoAdapter struct{}
 
-	return handler.ContinuePipeGroup(v), nil
+func (EchoAdapter) Bind(dst interface{}, args ...interface{}) error {
+	return args[0].(echo.Context).Bind(dst)
 }
 
-var ValidateRequestPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
-	ctx := args[0].(echo.Context)
-	request := v.FieldByName("Request").Addr().Interface()
-
-	if errs := validator.Validate(request); errs != nil {
-		if err := ctx.JSON(http.StatusBadRequest, errs); err != nil {
-			return handler.AbortPipeGroup, err
-		}
+func (EchoAdapter) JSON(status int, v interface{}, args ...interface{}) error {
+	if status == http.StatusNoContent {
+		return args[0].(echo.Context).NoContent(status)
 	}
 
-	return handler.ContinuePipeGroup(v), nil
+	return args[0].(echo.Context).JSON(status, v)
 }
 
-var CallActionPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
-	action := v.MethodByName("Action")
-
-	if !action.IsValid() {
-		return handler.ContinuePipeGroup(v), nil
-	}
-
-	if action.Type().NumIn() != 0 {
-		panic("action can't have arguments: " + v.Type().Name())
-	}
-
-	if action.Type().NumOut() != 2 {
-		panic("action wrong return types: " + v.Type().Name())
-	}
-
-	const errType = 1
-
-	if !action.Type().Out(errType).Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-		panic("action wrong return types, second should be error: " + v.Type().Name())
-	}
-
-	var emptyArgs []reflect.Value
-	returns := action.Call(emptyArgs)
-
-	ctx := args[0].(echo.Context)
-
-	if !returns[errType].IsNil() {
-		if err := ctx.JSON(http.StatusBadRequest, returns[errType].Interface()); err != nil {
-			return handler.AbortPipeGroup, err
-		}
-	}
-
-	return handler.ContinuePipeGroup(v), ctx.JSON(http.StatusOK, returns[0].Interface())
+func (EchoAdapter) Param(name string, args ...interface{}) string {
+	return args[0].(echo.Context).Param(name)
 }
 
-var NoActionsPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
-	panic("can't process action: no action methods")
+var StandardPipes = pipes.New(EchoAdapter{}, pipes.Config{
+	Validate: func(request interface{}) error {
+		return validator.Validate(request)
+	},
+	Error: func(err error) interface{} {
+		return err
+	},
+})
+
+var ActionPipes = handler.PipeGroup{
+	StandardPipes.BindRequest,
+	StandardPipes.ValidateRequest,
+	handler.PipeArray{StandardPipes.CallAction, StandardPipes.NoActions},
 }
 
 var EchoHandler handler.Converter = func(f handler.GenericHandlerFunc) interface{} {
@@ -102,18 +63,21 @@ func main() {
 		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.POST, echo.DELETE},
 	}))
 
-	e.GET("/articles", getHandler(action.GetArticles{}))
-	e.POST("/articles", getHandler(action.CreateArticle{}))
+	router := handler.NewRouter(ActionPipes, EchoHandler)
 
-	e.Logger.Fatal(e.Start(":1323"))
-}
+	err := router.Register(action.GetArticles{}, action.CreateArticle{})
+	if err != nil {
+		panic(err)
+	}
 
-func getHandler(h interface{}) echo.HandlerFunc {
-	handler, err := handler.New(ActionPipes, h, EchoHandler)
+	err = router.Mount(handler.MounterFunc(func(method, path string, h interface{}) error {
+		e.Add(method, path, h.(echo.HandlerFunc))
 
+		return nil
+	}))
 	if err != nil {
 		panic(err)
 	}
 
-	return handler.Handler().(echo.HandlerFunc)
+	e.Logger.Fatal(e.Start(":1323"))
 }
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/examples/echo-example/action"
+	"github.com/mykytanikitenko/go-handle/nethttp"
+	"github.com/mykytanikitenko/go-handle/pipes"
+	"gopkg.in/validator.v2"
+)
+
+// ActionPipes are standard net/http pipes what validate requests by `validate` tags,
+// nethttp.Pipes validate only requests what implement pipes.Validator
+var ActionPipes = pipes.New(nethttp.Adapter{}, pipes.Config{
+	Validate: func(request interface{}) error {
+		return validator.Validate(request)
+	},
+}).Group()
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	router := handler.NewRouter(ActionPipes, nethttp.Converter, handler.WithLogger(logger, handler.LogLevels{}))
+
+	err := router.Register(action.GetArticles{}, action.CreateArticle{})
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+
+	if err := router.Mount(nethttp.Mounter(mux)); err != nil {
+		panic(err)
+	}
+
+	// pipe tree as Mermaid flowchart for debugging
+	mux.HandleFunc("/debug/pipes", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(handler.DumpMermaid(ActionPipes)))
+	})
+
+	log.Fatal(http.ListenAndServe(":1323", mux))
+}
@@ -1,13 +1,20 @@
 package handler
 
 import (
-	"github.com/jinzhu/copier"
+	"context"
 	"reflect"
+	"time"
 )
 
 // Handler represents handler getter
 type Handler interface {
 	Handler() interface{}
+
+	// Metadata returns route metadata declared by Endpoint field
+	Metadata() Metadata
+
+	// Type returns struct type of handler instance
+	Type() reflect.Type
 }
 
 var _ Handler = (*handler)(nil)
@@ -15,21 +22,62 @@ var _ Handler = (*handler)(nil)
 type handler struct {
 	pipesGroup PipeGroup
 
+	// compiled pipesGroup
+	plan []instruction
+
 	// type value from what we extract constructor of type
 	t interface{}
 
+	// struct type of handler instance
+	typ reflect.Type
+
 	// constructor of type
-	ctor func() reflect.Value
+	ctor func(ctx context.Context, args []interface{}) (reflect.Value, error)
+
+	// route metadata of handler type
+	metadata Metadata
+
+	// resolves dependencies of constructor
+	resolver Resolver
+
+	// injects services to instance
+	injector *injector
 
 	// converter func
 	convertTo Converter
+
+	// extracts context from generic handler arguments
+	context func(args ...interface{}) context.Context
+
+	// recover panics of pipes
+	recovery bool
+
+	// observes execution of pipes, nil if there are no hooks
+	hook Hook
+
+	// descriptions of plan instructions for hooks
+	steps []Step
+
+	// writes errors as problem details, nil if errors are returned as is
+	problems *ProblemConfig
 }
 
 func (h *handler) init() error {
 
 	// When someone already bothered to pass constructor function
 	if ctor, alreadyCtor := h.t.(func() reflect.Value); alreadyCtor {
-		h.ctor = ctor
+		h.ctor = func(context.Context, []interface{}) (reflect.Value, error) {
+			return ctor(), nil
+		}
+
+		// constructor isn't called until the first request, type is declared by WithType
+		if h.typ != nil && h.typ.Kind() == reflect.Ptr {
+			h.typ = h.typ.Elem()
+		}
+
+		if h.typ != nil && h.typ.Kind() != reflect.Struct {
+			return ErrorTypeNotStruct
+		}
 
 		return nil
 	}
@@ -57,53 +105,38 @@ kindStruct:
 
 	// passed struct like New([]Pipe{pipe1, pipe2 ...}, MyHandler{})
 	if v.Kind() == reflect.Struct {
-		h.ctor = func() reflect.Value {
+		h.typ = v.Type()
 
-			// creating new instance of type
-			instance := reflect.New(
-				reflect.TypeOf(
-					reflect.Indirect(v).Interface(),
-				),
-			)
-
-			newInstance := reflect.Indirect(instance)
-
-			if !newInstance.CanAddr() {
-				panic("handler.init.ctor: not addressable new instance")
-			}
-
-			if !newInstance.Addr().CanInterface() {
-				panic("handler.init.ctor: not interfaceble new instance")
-			}
+		// field layout is computed once, so creating instance is cheap
+		cloner, err := newCloner(v.Type())
+		if err != nil {
+			return err
+		}
 
+		h.ctor = func(context.Context, []interface{}) (reflect.Value, error) {
 			// Copying passed values from general instance
-			if err := copier.Copy(newInstance.Addr().Interface(), v.Interface()); err != nil {
-				panic("handler.init.ctor: error copy new instance " + err.Error())
-			}
+			newInstance := cloner.clone(v)
 
 			if vPtr {
-				return newInstance.Addr()
+				return newInstance.Addr(), nil
 			}
 
-			return newInstance
+			return newInstance, nil
 		}
 
 		return nil
 	}
 
 	// passed func like New([]Pipe{pipe1, pipe2 ...}, func() *MyHandler { return new(MyHandler) })
+	// or with dependencies like func(ctx context.Context, db *sql.DB) (*MyHandler, error)
 	if v.Kind() == reflect.Func {
-		if v.Type().NumIn() != 0 {
-			return ErrorTCtorFuncHaveArguments
-		}
-
 		numOut := v.Type().NumOut()
 
 		if numOut == 0 {
 			return ErrorTCtorFuncVoid
 		}
 
-		if numOut > 1 {
+		if numOut > 2 || numOut == 2 && v.Type().Out(1) != errorType {
 			return ErrorTCtorFuncMoreThanOneReturnType
 		}
 
@@ -120,10 +153,38 @@ kindStruct:
 		}
 
 		if retType.Kind() == reflect.Struct {
-			h.ctor = func() reflect.Value {
-				var withEmptyParams []reflect.Value
+			h.typ = retType
+
+			params, err := ctorParams(v.Type(), h.resolver)
+			if err != nil {
+				return err
+			}
+
+			h.ctor = func(ctx context.Context, args []interface{}) (reflect.Value, error) {
+				in := make([]reflect.Value, len(params))
+
+				for i, param := range params {
+					value, err := param(ctx, args)
+					if err != nil {
+						return reflect.Value{}, err
+					}
+
+					in[i] = value
+				}
 
-				return v.Call(withEmptyParams)[first]
+				var out []reflect.Value
+
+				if v.Type().IsVariadic() {
+					out = v.CallSlice(in)
+				} else {
+					out = v.Call(in)
+				}
+
+				if numOut == 2 && !out[1].IsNil() {
+					return reflect.Value{}, out[1].Interface().(error)
+				}
+
+				return out[first], nil
 			}
 
 			return nil
@@ -139,72 +200,308 @@ kindStruct:
 //   var myHttpHandler http.Handler = h.Handler().(http.Handler)
 func (h *handler) Handler() interface{} {
 	handler := func(args ...interface{}) error {
-		// Creating new instance of handler
-		instance := h.ctor()
+		var e execution
+
+		err := h.execute(&e, args)
+
+		if err != nil && h.problems != nil {
+			return h.problems.write(e.requestContext(), err, args)
+		}
+
+		return err
+	}
+
+	return h.convertTo(handler)
+}
+
+// execute creates new instance of handler and runs compiled plan
+func (h *handler) execute(e *execution, args []interface{}) (err error) {
+	*e = execution{
+		args:     args,
+		plan:     h.plan,
+		pc:       constructing,
+		hook:     h.hook,
+		steps:    h.steps,
+		recovery: h.recovery,
+	}
+
+	e.setContext(h.beginRequest(h.context(args...)))
+
+	if h.recovery {
+		defer e.recover(&err)
+	}
+
+	// Creating new instance of handler
+	e.instance, err = h.ctor(e.ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if h.injector != nil {
+		if e.instance, err = h.injector.inject(e.ctx, e.instance); err != nil {
+			return err
+		}
+	}
+
+	err = e.run()
+	e.unwind(err)
+
+	return err
+}
+
+// constructing is program counter of execution
+// while handler instance is being created
+const constructing = -1
+
+// execution represents state of single generic handler call
+type execution struct {
+	ctx      context.Context
+	args     []interface{}
+	instance reflect.Value
+
+	// ctx.Done(), nil if ctx is never done
+	done <-chan struct{}
+
+	plan []instruction
+
+	// index of current instruction
+	pc int
+
+	// index of predicate what is matched by current opSwitch
+	predicate int
 
-		var executePipesArray func(pipes []Pipe) error
-		var executePipes func(pipes interface{}) (*reflect.Value, error)
+	// entered groups what have timeout or are observed by hook
+	frames []groupFrame
 
-		executePipesArray = func(pipes []Pipe) error {
-			for _, pipe := range pipes {
-				var err error
+	// whether execution was stopped by pipe placed directly in PipeGroup
+	halted bool
 
-				// executing pipe
-				instancePtr, err := executePipes(pipe)
+	hook  Hook
+	steps []Step
 
-				if err != nil {
-					return err
+	// whether panics are recovered, branches of parallel groups recover them on their own
+	recovery bool
+
+	// context returned by hook for root group, it holds spans and loggers of request
+	root context.Context
+
+	// context and start of pipe observed by hook
+	pipeCtx   context.Context
+	pipeStart time.Time
+}
+
+// groupFrame holds state of entered group
+type groupFrame struct {
+	// context what was before entering group
+	parent context.Context
+
+	// context returned by hook
+	ctx context.Context
+
+	// cancels timeout of group
+	cancel context.CancelFunc
+
+	// instruction what entered group
+	pc    int
+	start time.Time
+
+	// whether group was aborted by its pipe
+	aborted bool
+}
+
+func (e *execution) run() error {
+	// local copies are cheaper to update than fields of execution
+	instance := e.instance
+	plan := e.plan
+	observed := e.hook != nil
+
+	for pc := 0; pc < len(plan); {
+		i := &plan[pc]
+
+		// remembering current instruction for recovery
+		e.pc = pc
+
+		switch i.op {
+		// plan has groups only if they have timeout or are observed by hook
+		case opEnter:
+			e.enterGroup(pc, i)
+		case opExit:
+			e.exitGroup(nil)
+		case opParallel:
+			merged, err := e.parallel(i.parallel, instance)
+			if err != nil {
+				return err
+			}
+
+			instance = merged
+		case opSwitch:
+			chosen := len(i.predicates)
+
+			for k, predicate := range i.predicates {
+				e.predicate = k
+
+				if predicate.Match(instance, e.args...) {
+					chosen = k
+
+					break
+				}
+			}
+
+			pc = i.targets[chosen]
+
+			continue
+		case opJump:
+			pc = i.jump
+
+			continue
+		case opPipe:
+			// checking without locking context
+			if e.done != nil {
+				select {
+				case <-e.done:
+					return &ContextError{Err: e.ctx.Err()}
+				default:
 				}
+			}
+
+			var v *reflect.Value
+			var err error
+
+			// executing pipe
+			if observed {
+				v, err = e.callObserved(pc, i, instance)
+			} else if i.pipe != nil {
+				v, err = i.pipe(instance, e.args...)
+			} else {
+				v, err = i.contextPipe(e.ctx, instance, e.args...)
+			}
+
+			if err != nil {
+				return annotate(err, i)
+			}
+
+			// stop action when received nil
+			if v == AbortPipeGroup {
+				if i.abort == halt {
+					e.halted = true
+					e.instance = instance
 
-				// stop action when received nil
-				if instancePtr == nil {
 					return nil
 				}
 
-				instance = *instancePtr
+				if observed {
+					e.frames[len(e.frames)-1].aborted = true
+				}
+
+				pc = i.abort
+
+				continue
 			}
 
-			return nil
+			if i.replace {
+				instance = *v
+			}
 		}
 
-		executePipes = func(pipes interface{}) (*reflect.Value, error) {
-			switch pipe := pipes.(type) {
-			case Pipe:
-				return pipe(instance, args...)
-			case []Pipe:
-				err := executePipesArray(pipe)
-				if err != nil {
-					return nil, err
-				}
+		pc++
+	}
 
-				return &instance, nil
-			case PipeGroup:
-				var err error
-				var v *reflect.Value
+	// final instance is read by parallel group after its branch is executed
+	e.instance = instance
 
-				for _, group := range pipe {
-					v, err = executePipes(group)
+	return nil
+}
 
-					if err != nil {
-						return nil, err
-					}
+// callObserved calls pipe between BeforePipe and AfterPipe of hook
+func (e *execution) callObserved(pc int, i *instruction, instance reflect.Value) (v *reflect.Value, err error) {
+	step := e.steps[pc]
 
-					if v == AbortPipeGroup {
-						break
-					}
-				}
+	ctx := e.hook.BeforePipe(e.ctx, step)
+	e.pipeCtx, e.pipeStart = ctx, time.Now()
 
-				return v, err
-			}
+	if i.pipe != nil {
+		v, err = i.pipe(instance, e.args...)
+	} else {
+		v, err = i.contextPipe(ctx, instance, e.args...)
+	}
+
+	e.pipeCtx = nil
+
+	if err != nil {
+		err = annotate(err, i)
+	}
+
+	e.hook.AfterPipe(ctx, step, Result{
+		Duration: time.Since(e.pipeStart),
+		Aborted:  err == nil && v == AbortPipeGroup,
+		Err:      err,
+	})
+
+	return v, err
+}
+
+func (e *execution) setContext(ctx context.Context) {
+	e.ctx = ctx
+	e.done = ctx.Done()
+}
+
+// enterGroup enters group with timeout or group observed by hook
+func (e *execution) enterGroup(pc int, i *instruction) {
+	frame := groupFrame{parent: e.ctx, pc: pc}
+	ctx := e.ctx
+
+	if e.hook != nil {
+		ctx = e.hook.EnterGroup(ctx, e.steps[pc])
+		frame.ctx, frame.start = ctx, time.Now()
 
-			panic("Wrong type: " + reflect.TypeOf(pipes).Name())
+		if len(e.frames) == 0 && e.root == nil {
+			e.root = ctx
 		}
+	}
 
-		// Traversing pipe tree
-		_, err := executePipes(h.pipesGroup)
+	if i.timeout > 0 {
+		ctx, frame.cancel = context.WithTimeout(ctx, i.timeout)
+	}
 
-		return err
+	e.frames = append(e.frames, frame)
+	e.setContext(ctx)
+}
+
+// exitGroup restores context what was before entering the last entered group
+func (e *execution) exitGroup(err error) {
+	last := len(e.frames) - 1
+	frame := e.frames[last]
+
+	if frame.cancel != nil {
+		frame.cancel()
 	}
 
-	return h.convertTo(handler)
+	e.setContext(frame.parent)
+	e.frames = e.frames[:last]
+
+	if e.hook != nil {
+		e.hook.ExitGroup(frame.ctx, e.steps[frame.pc], Result{
+			Duration: time.Since(frame.start),
+			Aborted:  frame.aborted || e.halted,
+			Err:      err,
+		})
+	}
+}
+
+// requestContext returns context of root group annotated by hooks,
+// or context of request if there are no hooks
+func (e *execution) requestContext() context.Context {
+	if e.root != nil {
+		return e.root
+	}
+
+	return e.ctx
+}
+
+// unwind exits groups what were not exited
+// because execution was stopped
+func (e *execution) unwind(err error) {
+	for len(e.frames) > 0 {
+		e.exitGroup(err)
+	}
 }
@@ -195,19 +195,14 @@ func getPipeStep(t *testing.T, step int, currentExecutionStep *int) Pipe {
 	}
 }
 
-func Test_Handler_NotPipeType_ExpectsPanic(t *testing.T) {
+func Test_Handler_NotPipeType_ExpectsUnsupportedPipeTypeError(t *testing.T) {
 	const someInvalidType = 12345
 
 	pipes := PipeGroup{someInvalidType}
 
-	h, err := New(pipes, mockStruct{}, converterMock)
-	assert.NoError(t, err)
-
-	assert.Panics(t, func() {
-		handler := h.Handler()
+	_, err := New(pipes, mockStruct{}, converterMock)
 
-		err = handler.(func(*mockContext) error)(&mockContext{})
-	})
+	assert.True(t, errors.Is(err, ErrorUnsupportedPipeType))
 }
 
 func Test_Handler_PipeInPipeGroupReturnsError_ErrorFallthrougHandler(t *testing.T) {
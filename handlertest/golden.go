@@ -0,0 +1,257 @@
+package handlertest
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+// update makes Golden write golden files instead of comparing with them
+var update = flag.Bool("update-golden", false, "update golden files of handlertest.Golden")
+
+// RequestExt and GoldenExt are extensions of request fixtures and golden files
+const (
+	RequestExt = ".http"
+	GoldenExt  = ".golden"
+)
+
+// Golden runs each request fixture of dir against h and compares
+// recorded response with golden file of the fixture.
+// Run tests with -update-golden flag to write golden files.
+//
+// Fixture is request in HTTP/1 text format, file name is name of subtest:
+//
+//	POST /articles?draft=1
+//	Content-Type: application/json
+//
+//	{"title": "hello"}
+//
+// Golden file of testdata/articles/create.http is testdata/articles/create.golden:
+//
+//	HTTP/1.1 200 OK
+//	Content-Type: application/json
+//
+//	{"title":"hello"}
+//
+// Handler may be http.ServeMux with mounted routes, echo.Echo
+// or any other http.Handler, so tests run offline with real converter and pipes
+func Golden(t *testing.T, dir string, h http.Handler) {
+	t.Helper()
+
+	fixtures, err := filepath.Glob(filepath.Join(dir, "*"+RequestExt))
+	if err != nil {
+		t.Fatalf("handlertest: %v", err)
+	}
+
+	if len(fixtures) == 0 {
+		t.Fatalf("handlertest: no %s fixtures in %s", RequestExt, dir)
+	}
+
+	for _, fixture := range fixtures {
+		fixture := fixture
+
+		t.Run(strings.TrimSuffix(filepath.Base(fixture), RequestExt), func(t *testing.T) {
+			golden(t, fixture, h)
+		})
+	}
+}
+
+// GoldenRoutes is Golden what also fails for routes without request fixtures,
+// so each registered handler is covered by contract test
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	err := router.Mount(nethttp.Mounter(mux))
+//
+//	handlertest.GoldenRoutes(t, "testdata/golden", mux, router.Routes())
+func GoldenRoutes(t *testing.T, dir string, h http.Handler, routes []handler.RouteInfo) {
+	t.Helper()
+
+	Golden(t, dir, h)
+
+	missing, err := uncoveredRoutes(dir, routes)
+	if err != nil {
+		t.Fatalf("handlertest: %v", err)
+	}
+
+	for _, route := range missing {
+		t.Errorf("handlertest: route %s has no request fixture in %s", route, dir)
+	}
+}
+
+func golden(t *testing.T, fixture string, h http.Handler) {
+	t.Helper()
+
+	r, err := readRequest(fixture)
+	if err != nil {
+		t.Fatalf("handlertest: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	actual := formatResponse(w)
+	path := strings.TrimSuffix(fixture, RequestExt) + GoldenExt
+
+	if *update {
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("handlertest: %v", err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("handlertest: golden file %s doesn't exist, run tests with -update-golden flag", path)
+	}
+
+	if err != nil {
+		t.Fatalf("handlertest: %v", err)
+	}
+
+	if !bytes.Equal(normalize(expected), actual) {
+		t.Errorf("handlertest: response differs from %s\nexpected:\n%s\nactual:\n%s", path, expected, actual)
+	}
+}
+
+// readRequest reads request fixture
+func readRequest(path string) (*http.Request, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	head, body, _ := strings.Cut(string(normalize(content)), "\n\n")
+
+	scanner := bufio.NewScanner(strings.NewReader(head))
+
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("%s: empty request fixture", path)
+	}
+
+	// request line may omit protocol, like "GET /articles"
+	line := strings.Fields(scanner.Text())
+	if len(line) < 2 {
+		return nil, fmt.Errorf("%s: invalid request line %q", path, scanner.Text())
+	}
+
+	r := httptest.NewRequest(line[0], line[1], strings.NewReader(body))
+
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid header %q", path, scanner.Text())
+		}
+
+		if key = strings.TrimSpace(key); strings.EqualFold(key, "Host") {
+			r.Host = strings.TrimSpace(value)
+		} else {
+			r.Header.Add(key, strings.TrimSpace(value))
+		}
+	}
+
+	return r, nil
+}
+
+// formatResponse formats recorded response with sorted headers
+func formatResponse(w *httptest.ResponseRecorder) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "HTTP/1.1 %d %s\n", w.Code, http.StatusText(w.Code))
+
+	header := w.Result().Header
+
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(&b, "%s: %s\n", key, value)
+		}
+	}
+
+	b.WriteString("\n")
+	b.Write(w.Body.Bytes())
+
+	return b.Bytes()
+}
+
+// normalize replaces CRLF line endings, so fixtures may be edited on any platform
+func normalize(content []byte) []byte {
+	return bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+}
+
+// uncoveredRoutes returns routes what don't match any request fixture of dir
+func uncoveredRoutes(dir string, routes []handler.RouteInfo) ([]handler.RouteInfo, error) {
+	fixtures, err := filepath.Glob(filepath.Join(dir, "*"+RequestExt))
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []*http.Request
+
+	for _, fixture := range fixtures {
+		r, err := readRequest(fixture)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, r)
+	}
+
+	var missing []handler.RouteInfo
+
+	for _, route := range routes {
+		covered := false
+
+		for _, r := range requests {
+			if r.Method == route.Method && matchPath(route.Path, r.URL.Path) {
+				covered = true
+
+				break
+			}
+		}
+
+		if !covered {
+			missing = append(missing, route)
+		}
+	}
+
+	return missing, nil
+}
+
+// matchPath reports whether path matches route path with parameters like :id or {id}
+func matchPath(pattern, path string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		parameter := strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+
+		if !parameter && segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return true
+}
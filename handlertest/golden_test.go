@@ -0,0 +1,63 @@
+package handlertest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/nethttp"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockGetAction struct {
+	handler.Endpoint `method:"GET" path:"/articles/:id"`
+
+	Request struct {
+		ID     int    `param:"id"`
+		Search string `query:"search"`
+	}
+}
+
+func (a *mockGetAction) Action() (interface{}, error) {
+	return a.Request, nil
+}
+
+func newRouter(t *testing.T) (*handler.Router, *http.ServeMux) {
+	router := handler.NewRouter(nethttp.Pipes, nethttp.Converter)
+
+	assert.NoError(t, router.Handle(handler.Route{Method: "POST", Path: "/articles", T: mockAction{}}))
+	assert.NoError(t, router.Register(mockGetAction{}))
+
+	mux := http.NewServeMux()
+	assert.NoError(t, router.Mount(nethttp.Mounter(mux)))
+
+	return router, mux
+}
+
+func Test_GoldenRoutes_ExpectResponsesMatchGoldenFiles(t *testing.T) {
+	router, mux := newRouter(t)
+
+	GoldenRoutes(t, "testdata/golden", mux, router.Routes())
+}
+
+func Test_UncoveredRoutes_ExpectRoutesWithoutFixtures(t *testing.T) {
+	router, _ := newRouter(t)
+
+	assert.NoError(t, router.Handle(handler.Route{Method: "DELETE", Path: "/articles/{id}", T: mockAction{}}))
+
+	missing, err := uncoveredRoutes("testdata/golden", router.Routes())
+
+	assert.NoError(t, err)
+	assert.Len(t, missing, 1)
+	assert.Equal(t, "DELETE /articles/{id}", missing[0].String())
+}
+
+func Test_ReadRequest_ExpectMethodTargetHeadersAndBody(t *testing.T) {
+	r, err := readRequest("testdata/golden/create.http")
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, r.Method)
+	assert.Equal(t, "/articles", r.URL.Path)
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	assert.Equal(t, int64(len(`{"title": "hello"}`)+1), r.ContentLength)
+}
@@ -0,0 +1,324 @@
+// Package handlertest drives handlers in tests without real server or framework.
+// It records which pipes ran, in what order, what they returned
+// and final state of handler instance
+//
+// Example:
+//
+//	func Test_CreateArticle_InvalidTitle_ExpectValidationAbort(t *testing.T) {
+//		h := handlertest.New(t, nethttp.Pipes, action.CreateArticle{})
+//
+//		result := h.Do(httptest.NewRequest("POST", "/articles", strings.NewReader(`{"title":""}`)))
+//
+//		result.AssertRan("InjectServices", "Authorize", "BindRequest", "ValidateRequest").
+//			AssertNotRan("CallAction").
+//			AssertStatus(http.StatusBadRequest)
+//	}
+package handlertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/nethttp"
+)
+
+// Call represents pipe call
+type Call struct {
+	Name string
+	Path handler.Path
+
+	Outcome  handler.Outcome
+	Err      error
+	Duration time.Duration
+}
+
+// Harness builds handler of pipes and instance of T and runs it.
+// Runs of the same harness should not be parallel
+type Harness[T any] struct {
+	t testing.TB
+
+	handler handler.GenericHandlerFunc
+
+	recorder *recorder
+	instance reflect.Value
+}
+
+// New creates handler of pipes what creates copies of instance for each run,
+// instance is copied like handler.New copies struct passed to it.
+// T should be struct type. Options are passed to handler.New
+func New[T any](t testing.TB, pipes handler.PipeGroup, instance T, options ...handler.Option) *Harness[T] {
+	t.Helper()
+
+	if reflect.TypeOf(instance).Kind() != reflect.Struct {
+		t.Fatalf("handlertest: instance should be struct, got %T", instance)
+	}
+
+	h := &Harness[T]{
+		t:        t,
+		recorder: &recorder{},
+	}
+
+	clone, err := handler.Prototype(instance)
+	if err != nil {
+		t.Fatalf("handlertest: %v", err)
+	}
+
+	// instance created for run is kept to read its final state
+	ctor := func() reflect.Value {
+		h.instance = clone()
+
+		return h.instance
+	}
+
+	options = append(append([]handler.Option{}, options...),
+		handler.WithType(reflect.TypeOf(instance)), handler.WithHooks(h.recorder))
+
+	created, err := handler.New(pipes, ctor, func(f handler.GenericHandlerFunc) interface{} {
+		return f
+	}, options...)
+	if err != nil {
+		t.Fatalf("handlertest: %v", err)
+	}
+
+	h.handler = created.Handler().(handler.GenericHandlerFunc)
+
+	return h
+}
+
+// Run runs handler with args passed to pipes
+func (h *Harness[T]) Run(args ...interface{}) *Result[T] {
+	h.t.Helper()
+
+	h.recorder.reset()
+	h.instance = reflect.Value{}
+
+	err := h.handler(args...)
+
+	result := &Result[T]{
+		t:     h.t,
+		Err:   err,
+		Calls: h.recorder.calls(),
+	}
+
+	if h.instance.IsValid() {
+		result.Instance = h.instance.Interface().(T)
+	}
+
+	return result
+}
+
+// Do runs handler through nethttp.Converter, so error what is returned
+// before anything is written responds with status 500 like in server.
+// Response is recorded
+func (h *Harness[T]) Do(r *http.Request) *Result[T] {
+	h.t.Helper()
+
+	w := httptest.NewRecorder()
+
+	var result *Result[T]
+
+	serve := nethttp.Converter(func(args ...interface{}) error {
+		result = h.Run(args...)
+
+		return result.Err
+	}).(http.HandlerFunc)
+
+	serve(w, r)
+
+	result.Response = w
+
+	return result
+}
+
+// Result represents result of handler run
+type Result[T any] struct {
+	t testing.TB
+
+	// Err is error returned by handler
+	Err error
+
+	// Calls are pipe calls in order of execution
+	Calls []Call
+
+	// Instance is state of handler instance after run.
+	// Values returned by pipes placed in []Pipe what replace instance are not tracked
+	Instance T
+
+	// Response is recorded response of Do
+	Response *httptest.ResponseRecorder
+}
+
+// Names returns names of called pipes in order of execution
+func (r *Result[T]) Names() []string {
+	names := make([]string, len(r.Calls))
+
+	for i, call := range r.Calls {
+		names[i] = call.Name
+	}
+
+	return names
+}
+
+// Call returns the first call of pipe with name
+func (r *Result[T]) Call(name string) (Call, bool) {
+	for _, call := range r.Calls {
+		if call.Name == name {
+			return call, true
+		}
+	}
+
+	return Call{}, false
+}
+
+// AbortedAt returns the first call of pipe what returned AbortPipeGroup
+func (r *Result[T]) AbortedAt() (Call, bool) {
+	for _, call := range r.Calls {
+		if call.Outcome == handler.OutcomeAborted {
+			return call, true
+		}
+	}
+
+	return Call{}, false
+}
+
+// AssertRan checks that exactly pipes with names ran in order
+func (r *Result[T]) AssertRan(names ...string) *Result[T] {
+	r.t.Helper()
+
+	if ran := r.Names(); !reflect.DeepEqual(ran, names) {
+		r.t.Errorf("handlertest: expected pipes %s to run, ran %s", list(names), list(ran))
+	}
+
+	return r
+}
+
+// AssertNotRan checks that pipes with names didn't run
+func (r *Result[T]) AssertNotRan(names ...string) *Result[T] {
+	r.t.Helper()
+
+	for _, name := range names {
+		if _, ok := r.Call(name); ok {
+			r.t.Errorf("handlertest: expected pipe %s not to run", name)
+		}
+	}
+
+	return r
+}
+
+// AssertAbortedAt checks that the first pipe what returned AbortPipeGroup has name
+func (r *Result[T]) AssertAbortedAt(name string) *Result[T] {
+	r.t.Helper()
+
+	call, ok := r.AbortedAt()
+
+	switch {
+	case !ok:
+		r.t.Errorf("handlertest: expected pipe %s to abort, no pipe aborted", name)
+	case call.Name != name:
+		r.t.Errorf("handlertest: expected pipe %s to abort, %s aborted at %s", name, call.Name, call.Path)
+	}
+
+	return r
+}
+
+// AssertNoError checks that handler returned no error
+func (r *Result[T]) AssertNoError() *Result[T] {
+	r.t.Helper()
+
+	if r.Err != nil {
+		r.t.Errorf("handlertest: unexpected error: %v", r.Err)
+	}
+
+	return r
+}
+
+// AssertError checks that handler returned error what matches target by errors.Is,
+// any error is expected if target is nil
+func (r *Result[T]) AssertError(target error) *Result[T] {
+	r.t.Helper()
+
+	switch {
+	case r.Err == nil:
+		r.t.Errorf("handlertest: expected error, got nil")
+	case target != nil && !errors.Is(r.Err, target):
+		r.t.Errorf("handlertest: expected error %v, got %v", target, r.Err)
+	}
+
+	return r
+}
+
+// AssertInstance checks instance state with f
+//
+// Example:
+//
+//	result.AssertInstance(func(t testing.TB, action action.CreateArticle) {
+//		assert.Equal(t, "hello", action.Request.Title)
+//	})
+func (r *Result[T]) AssertInstance(f func(t testing.TB, instance T)) *Result[T] {
+	r.t.Helper()
+
+	f(r.t, r.Instance)
+
+	return r
+}
+
+// AssertStatus checks status of response recorded by Do
+func (r *Result[T]) AssertStatus(status int) *Result[T] {
+	r.t.Helper()
+
+	switch {
+	case r.Response == nil:
+		r.t.Errorf("handlertest: expected status %d, no response recorded, use Do", status)
+	case r.Response.Code != status:
+		r.t.Errorf("handlertest: expected status %d, got %d: %s", status, r.Response.Code, r.Response.Body)
+	}
+
+	return r
+}
+
+func list(names []string) string {
+	return "[" + strings.Join(names, " ") + "]"
+}
+
+// recorder is hook what records pipe calls
+type recorder struct {
+	handler.NopHook
+
+	mu       sync.Mutex
+	recorded []Call
+}
+
+func (r *recorder) AfterPipe(ctx context.Context, pipe handler.Step, result handler.Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recorded = append(r.recorded, Call{
+		Name:     pipe.Name,
+		Path:     pipe.Path,
+		Outcome:  result.Outcome(),
+		Err:      result.Err,
+		Duration: result.Duration,
+	})
+}
+
+func (r *recorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recorded = nil
+}
+
+func (r *recorder) calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call{}, r.recorded...)
+}
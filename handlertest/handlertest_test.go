@@ -0,0 +1,163 @@
+package handlertest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/nethttp"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	mockRequest struct {
+		Title string `json:"title"`
+	}
+
+	mockAction struct {
+		Request mockRequest
+		Called  bool
+	}
+
+	mockInstance struct {
+		Steps []string
+	}
+)
+
+func (r *mockRequest) Validate() error {
+	if r.Title == "" {
+		return errors.New("title is required")
+	}
+
+	return nil
+}
+
+func (a *mockAction) Action() (interface{}, error) {
+	a.Called = true
+
+	return a.Request, nil
+}
+
+// mockTB records failures instead of failing test
+type mockTB struct {
+	testing.TB
+
+	errors []string
+}
+
+func (t *mockTB) Helper() {}
+
+func (t *mockTB) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func step(name string) handler.NamedPipe {
+	return handler.Named(name, func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		steps := v.FieldByName("Steps")
+		steps.Set(reflect.Append(steps, reflect.ValueOf(name)))
+
+		return handler.ContinuePipeGroup(v), nil
+	})
+}
+
+var abort = handler.Named("abort", func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	return handler.AbortPipeGroup, nil
+})
+
+func Test_Harness_Do_ExpectPipesAndResponseRecorded(t *testing.T) {
+	h := New(t, nethttp.Pipes, mockAction{})
+
+	result := h.Do(httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title":"hello"}`)))
+
+	result.AssertNoError().
+		AssertRan("InjectServices", "Authorize", "BindRequest", "ValidateRequest", "CallAction").
+		AssertNotRan("Render").
+		AssertAbortedAt("CallAction").
+		AssertStatus(http.StatusOK).
+		AssertInstance(func(t testing.TB, action mockAction) {
+			assert.True(t, action.Called)
+			assert.Equal(t, "hello", action.Request.Title)
+		})
+
+	assert.JSONEq(t, `{"title":"hello"}`, result.Response.Body.String())
+}
+
+func Test_Harness_Do_InvalidRequest_ExpectActionNotCalled(t *testing.T) {
+	h := New(t, nethttp.Pipes, mockAction{})
+
+	h.Do(httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{}`))).
+		AssertRan("InjectServices", "Authorize", "BindRequest", "ValidateRequest").
+		AssertAbortedAt("ValidateRequest").
+		AssertStatus(http.StatusBadRequest).
+		AssertInstance(func(t testing.TB, action mockAction) {
+			assert.False(t, action.Called)
+		})
+}
+
+func Test_Harness_Run_ExpectFreshInstancePerRun(t *testing.T) {
+	h := New(t, handler.PipeGroup{handler.PipeArray{step("first"), abort, step("skipped")}, step("last")}, mockInstance{})
+
+	for i := 0; i < 2; i++ {
+		result := h.Run()
+
+		result.AssertNoError().AssertRan("first", "abort", "last").AssertNotRan("skipped")
+
+		assert.Equal(t, []string{"first", "last"}, result.Instance.Steps)
+
+		call, ok := result.AbortedAt()
+		assert.True(t, ok)
+		assert.Equal(t, handler.Path{0, 1}, call.Path)
+	}
+}
+
+func Test_Harness_Run_ExpectInstanceClonedLikeHandlerNew(t *testing.T) {
+	template := mockInstance{Steps: make([]string, 0, 4)}
+
+	New(t, handler.PipeGroup{step("first")}, template).Run().AssertRan("first")
+
+	assert.Equal(t, []string{""}, template.Steps[:1], "slice of template is deep copied")
+}
+
+func Test_Harness_Do_PipeFails_ExpectConverterResponse(t *testing.T) {
+	failing := handler.Named("failing", func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return handler.AbortPipeGroup, errors.New("failed")
+	})
+
+	result := New(t, handler.PipeGroup{failing}, mockInstance{}).Do(httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Error(t, result.Err)
+	result.AssertStatus(http.StatusInternalServerError)
+}
+
+func Test_Result_Assertions_ExpectFailuresReported(t *testing.T) {
+	mockError := errors.New("failed")
+
+	failing := handler.Named("failing", func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return handler.AbortPipeGroup, mockError
+	})
+
+	tb := &mockTB{TB: t}
+
+	result := New(tb, handler.PipeGroup{step("first"), failing}, mockInstance{}).Run()
+
+	result.AssertError(mockError).
+		AssertRan("first").
+		AssertNotRan("failing").
+		AssertAbortedAt("failing").
+		AssertNoError().
+		AssertStatus(http.StatusOK)
+
+	assert.Equal(t, handler.OutcomeError, result.Calls[1].Outcome)
+	assert.Equal(t, []string{
+		"handlertest: expected pipes [first] to run, ran [first failing]",
+		"handlertest: expected pipe failing not to run",
+		"handlertest: expected pipe failing to abort, no pipe aborted",
+		"handlertest: unexpected error: failed",
+		"handlertest: expected status 200, no response recorded, use Do",
+	}, tb.errors)
+}
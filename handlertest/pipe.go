@@ -0,0 +1,253 @@
+package handlertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+// Change represents changed exported field of instance, like Request.Title
+type Change struct {
+	Field  string
+	Before interface{}
+	After  interface{}
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %#v -> %#v", c.Field, c.Before, c.After)
+}
+
+// PipeResult represents result of single pipe call
+type PipeResult[T any] struct {
+	t testing.TB
+
+	// Value is value returned by pipe
+	Value *reflect.Value
+	Err   error
+
+	Outcome handler.Outcome
+
+	// Before is instance passed to pipe.
+	// After is value returned by pipe if it's T, or instance after pipe call otherwise
+	Before T
+	After  T
+
+	// Changes are changed exported fields, nested structs are compared by fields
+	Changes []Change
+}
+
+// RunPipe calls pipe with addressable copy of instance and args. Instance is copied
+// by handler.Prototype as executor does for struct passed to handler.New, so pipe
+// doesn't change slices and maps of instance and Before.
+// Pipe is handler.Pipe or handler.NamedPipe created by handler.Named
+//
+// Example:
+//
+//	w, args := handlertest.HTTPArgs(httptest.NewRequest("POST", "/articles", body))
+//
+//	handlertest.RunPipe(t, nethttp.BindRequestPipe, action.CreateArticle{}, args...).
+//		AssertContinued().
+//		AssertChanged("Request.Title")
+func RunPipe[T any](t testing.TB, pipe interface{}, instance T, args ...interface{}) *PipeResult[T] {
+	t.Helper()
+
+	var call handler.Pipe
+
+	switch p := unnamed(pipe).(type) {
+	case handler.Pipe:
+		call = p
+	case func(reflect.Value, ...interface{}) (*reflect.Value, error):
+		call = p
+	default:
+		t.Fatalf("handlertest: pipe should be handler.Pipe, got %T", pipe)
+
+		return nil
+	}
+
+	return runPipe(t, instance, func(v reflect.Value) (*reflect.Value, error) {
+		return call(v, args...)
+	})
+}
+
+// RunContextPipe is RunPipe for handler.ContextPipe or handler.NamedPipe created by handler.NamedContext
+func RunContextPipe[T any](t testing.TB, ctx context.Context, pipe interface{}, instance T, args ...interface{}) *PipeResult[T] {
+	t.Helper()
+
+	var call handler.ContextPipe
+
+	switch p := unnamed(pipe).(type) {
+	case handler.ContextPipe:
+		call = p
+	case func(context.Context, reflect.Value, ...interface{}) (*reflect.Value, error):
+		call = p
+	default:
+		t.Fatalf("handlertest: pipe should be handler.ContextPipe, got %T", pipe)
+
+		return nil
+	}
+
+	return runPipe(t, instance, func(v reflect.Value) (*reflect.Value, error) {
+		return call(ctx, v, args...)
+	})
+}
+
+// unnamed returns pipe of handler.NamedPipe, other pipes are returned as is
+func unnamed(pipe interface{}) interface{} {
+	if named, ok := pipe.(handler.NamedPipe); ok {
+		return named.Pipe
+	}
+
+	return pipe
+}
+
+func runPipe[T any](t testing.TB, instance T, call func(v reflect.Value) (*reflect.Value, error)) *PipeResult[T] {
+	t.Helper()
+
+	ctor, err := handler.Prototype(instance)
+	if err != nil {
+		t.Fatalf("handlertest: instance should be struct, got %T: %v", instance, err)
+
+		return nil
+	}
+
+	// pipe gets its own copy, so Before keeps instance as it was
+	v := ctor()
+
+	value, err := call(v)
+
+	result := &PipeResult[T]{
+		t:      t,
+		Value:  value,
+		Err:    err,
+		Before: ctor().Interface().(T),
+		After:  v.Interface().(T),
+	}
+
+	switch {
+	case err != nil:
+		result.Outcome = handler.OutcomeError
+	case value == handler.AbortPipeGroup:
+		result.Outcome = handler.OutcomeAborted
+	case value.IsValid():
+		if returned, ok := reflect.Indirect(*value).Interface().(T); ok {
+			result.After = returned
+		}
+	}
+
+	result.Changes = diff("", reflect.ValueOf(result.Before), reflect.ValueOf(result.After))
+
+	return result
+}
+
+// HTTPArgs returns arguments of net/http converter for request and recorder of response
+func HTTPArgs(r *http.Request) (*httptest.ResponseRecorder, []interface{}) {
+	w := httptest.NewRecorder()
+
+	return w, []interface{}{w, r}
+}
+
+// AssertContinued checks that pipe returned value and no error
+func (r *PipeResult[T]) AssertContinued() *PipeResult[T] {
+	r.t.Helper()
+
+	return r.assertOutcome(handler.OutcomeOK)
+}
+
+// AssertAborted checks that pipe returned AbortPipeGroup and no error
+func (r *PipeResult[T]) AssertAborted() *PipeResult[T] {
+	r.t.Helper()
+
+	return r.assertOutcome(handler.OutcomeAborted)
+}
+
+// AssertError checks that pipe returned error what matches target by errors.Is,
+// any error is expected if target is nil
+func (r *PipeResult[T]) AssertError(target error) *PipeResult[T] {
+	r.t.Helper()
+
+	switch {
+	case r.Err == nil:
+		r.t.Errorf("handlertest: expected pipe error, got outcome %s", r.Outcome)
+	case target != nil && !errors.Is(r.Err, target):
+		r.t.Errorf("handlertest: expected pipe error %v, got %v", target, r.Err)
+	}
+
+	return r
+}
+
+func (r *PipeResult[T]) assertOutcome(outcome handler.Outcome) *PipeResult[T] {
+	r.t.Helper()
+
+	switch {
+	case r.Err != nil:
+		r.t.Errorf("handlertest: expected pipe outcome %s, got error: %v", outcome, r.Err)
+	case r.Outcome != outcome:
+		r.t.Errorf("handlertest: expected pipe outcome %s, got %s", outcome, r.Outcome)
+	}
+
+	return r
+}
+
+// AssertChanged checks that exactly fields with names were changed by pipe
+func (r *PipeResult[T]) AssertChanged(fields ...string) *PipeResult[T] {
+	r.t.Helper()
+
+	changed := make([]string, len(r.Changes))
+
+	for i, change := range r.Changes {
+		changed[i] = change.Field
+	}
+
+	if len(fields) == 0 && len(changed) == 0 {
+		return r
+	}
+
+	if !reflect.DeepEqual(changed, fields) {
+		r.t.Errorf("handlertest: expected fields %s to change, changed %v", list(fields), r.Changes)
+	}
+
+	return r
+}
+
+// AssertUnchanged checks that pipe didn't change instance
+func (r *PipeResult[T]) AssertUnchanged() *PipeResult[T] {
+	r.t.Helper()
+
+	return r.AssertChanged()
+}
+
+// diff returns changed exported fields of structs in order of declaration
+func diff(prefix string, before, after reflect.Value) []Change {
+	if before.Kind() != reflect.Struct || before.Type() != after.Type() {
+		if reflect.DeepEqual(before.Interface(), after.Interface()) {
+			return nil
+		}
+
+		return []Change{{Field: prefix, Before: before.Interface(), After: after.Interface()}}
+	}
+
+	var changes []Change
+
+	for i := 0; i < before.NumField(); i++ {
+		field := before.Type().Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		changes = append(changes, diff(name, before.Field(i), after.Field(i))...)
+	}
+
+	return changes
+}
@@ -0,0 +1,99 @@
+package handlertest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/nethttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RunPipe_BindRequest_ExpectRequestChanged(t *testing.T) {
+	_, args := HTTPArgs(httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title":"hello"}`)))
+
+	result := RunPipe(t, nethttp.BindRequestPipe, mockAction{}, args...).
+		AssertContinued().
+		AssertChanged("Request.Title")
+
+	assert.Equal(t, []Change{{Field: "Request.Title", Before: "", After: "hello"}}, result.Changes)
+	assert.Equal(t, "hello", result.After.Request.Title)
+	assert.Empty(t, result.Before.Request.Title)
+}
+
+func Test_RunPipe_ValidateRequest_ExpectAbortedAndBadRequest(t *testing.T) {
+	w, args := HTTPArgs(httptest.NewRequest(http.MethodPost, "/articles", nil))
+
+	RunPipe(t, nethttp.ValidateRequestPipe, mockAction{}, args...).
+		AssertAborted().
+		AssertUnchanged()
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func Test_RunContextPipe_ExpectContextAndReturnedValue(t *testing.T) {
+	type key struct{}
+
+	ctx := context.WithValue(context.Background(), key{}, "from context")
+
+	var pipe handler.ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		replaced := reflect.ValueOf(mockInstance{Steps: []string{ctx.Value(key{}).(string)}})
+
+		return &replaced, nil
+	}
+
+	result := RunContextPipe(t, ctx, pipe, mockInstance{}).AssertContinued().AssertChanged("Steps")
+
+	assert.Equal(t, []string{"from context"}, result.After.Steps)
+}
+
+func Test_RunPipe_ChangesSliceAndMapInPlace_ExpectChangedAndInstanceNotModified(t *testing.T) {
+	type tagged struct {
+		Tags   []string
+		Counts map[string]int
+	}
+
+	var pipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		v.FieldByName("Tags").Index(0).SetString("changed")
+		v.FieldByName("Counts").SetMapIndex(reflect.ValueOf("a"), reflect.ValueOf(2))
+
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	instance := tagged{Tags: []string{"go"}, Counts: map[string]int{"a": 1}}
+
+	result := RunPipe(t, pipe, instance).AssertContinued().AssertChanged("Tags", "Counts")
+
+	assert.Equal(t, tagged{Tags: []string{"go"}, Counts: map[string]int{"a": 1}}, result.Before)
+	assert.Equal(t, tagged{Tags: []string{"changed"}, Counts: map[string]int{"a": 2}}, result.After)
+	assert.Equal(t, tagged{Tags: []string{"go"}, Counts: map[string]int{"a": 1}}, instance, "instance is not modified")
+}
+
+func Test_PipeResult_Assertions_ExpectFailuresReported(t *testing.T) {
+	mockError := errors.New("failed")
+
+	var failing handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		v.FieldByName("Steps").Set(reflect.ValueOf([]string{"failed"}))
+
+		return handler.AbortPipeGroup, mockError
+	}
+
+	tb := &mockTB{TB: t}
+
+	RunPipe(tb, failing, mockInstance{}).
+		AssertError(mockError).
+		AssertContinued().
+		AssertError(errors.New("other")).
+		AssertUnchanged()
+
+	assert.Equal(t, []string{
+		"handlertest: expected pipe outcome ok, got error: failed",
+		"handlertest: expected pipe error other, got failed",
+		`handlertest: expected fields [] to change, changed [Steps: []string(nil) -> []string{"failed"}]`,
+	}, tb.errors)
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// StepKind represents kind of element of PipeGroup tree
+type StepKind uint8
+
+const (
+	// StepPipe is Pipe or ContextPipe
+	StepPipe StepKind = iota
+
+	// StepGroup is PipeGroup
+	StepGroup
+
+	// StepArray is []Pipe
+	StepArray
+
+	// StepTimeout is TimeoutGroup
+	StepTimeout
+
+	// StepParallel is ParallelGroup
+	StepParallel
+
+	// StepIf is IfGroup
+	StepIf
+
+	// StepSwitch is SwitchGroup
+	StepSwitch
+)
+
+func (k StepKind) String() string {
+	switch k {
+	case StepPipe:
+		return "pipe"
+	case StepGroup:
+		return "group"
+	case StepArray:
+		return "array"
+	case StepTimeout:
+		return "timeout"
+	case StepParallel:
+		return "parallel"
+	case StepIf:
+		return "if"
+	case StepSwitch:
+		return "switch"
+	}
+
+	return "unknown"
+}
+
+// Step describes pipe or group of pipes of handler
+type Step struct {
+	// Struct type of handler instance, nil if unknown
+	Handler reflect.Type
+
+	Kind StepKind
+
+	// Name of pipe func, or kind of group
+	Name string
+
+	// Description and tags of pipe created by Named
+	Description string
+	Tags        []string
+
+	// Path in PipeGroup tree, root group has empty path
+	Path Path
+
+	// Timeout of TimeoutGroup
+	Timeout time.Duration
+}
+
+// Result represents result of pipe or group execution
+type Result struct {
+	Duration time.Duration
+
+	// Aborted is true if pipe returned AbortPipeGroup,
+	// or group was aborted by its pipe
+	Aborted bool
+
+	// Err is error returned by pipe or error what stopped execution of group
+	Err error
+}
+
+// Hook observes execution of pipes.
+// Context returned by EnterGroup and BeforePipe is passed to ContextPipe
+// and to ExitGroup and AfterPipe of the same step, so hook can keep
+// spans or other per-step values in it
+type Hook interface {
+	EnterGroup(ctx context.Context, group Step) context.Context
+	ExitGroup(ctx context.Context, group Step, result Result)
+
+	BeforePipe(ctx context.Context, pipe Step) context.Context
+	AfterPipe(ctx context.Context, pipe Step, result Result)
+}
+
+// NopHook does nothing, embed it to implement only some methods of Hook
+type NopHook struct{}
+
+var _ Hook = NopHook{}
+
+func (NopHook) EnterGroup(ctx context.Context, group Step) context.Context {
+	return ctx
+}
+
+func (NopHook) ExitGroup(ctx context.Context, group Step, result Result) {}
+
+func (NopHook) BeforePipe(ctx context.Context, pipe Step) context.Context {
+	return ctx
+}
+
+func (NopHook) AfterPipe(ctx context.Context, pipe Step, result Result) {}
+
+// WithHooks adds hooks what observe execution of pipes.
+// Hooks are entered in order and exited in reverse order
+//
+// Example:
+//
+//	handler.New(pipes, MyHandler{}, converter, handler.WithHooks(otelhandler.New(nil)))
+func WithHooks(hooks ...Hook) Option {
+	return func(h *handler) {
+		if existing, ok := h.hook.(hookChain); ok {
+			h.hook = append(existing, hooks...)
+
+			return
+		}
+
+		h.hook = hookChain(hooks)
+	}
+}
+
+// hookChain calls hooks in order on enter and in reverse order on exit
+type hookChain []Hook
+
+func (c hookChain) EnterGroup(ctx context.Context, group Step) context.Context {
+	for _, hook := range c {
+		ctx = hook.EnterGroup(ctx, group)
+	}
+
+	return ctx
+}
+
+func (c hookChain) ExitGroup(ctx context.Context, group Step, result Result) {
+	for i := len(c) - 1; i >= 0; i-- {
+		c[i].ExitGroup(ctx, group, result)
+	}
+}
+
+func (c hookChain) BeforePipe(ctx context.Context, pipe Step) context.Context {
+	for _, hook := range c {
+		ctx = hook.BeforePipe(ctx, pipe)
+	}
+
+	return ctx
+}
+
+func (c hookChain) AfterPipe(ctx context.Context, pipe Step, result Result) {
+	for i := len(c) - 1; i >= 0; i-- {
+		c[i].AfterPipe(ctx, pipe, result)
+	}
+}
+
+// steps describes instructions of plan, enter and exit of group share the same step
+func steps(plan []instruction, t reflect.Type) []Step {
+	steps := make([]Step, len(plan))
+
+	for pc, i := range plan {
+		step := Step{
+			Handler: t,
+			Path:    i.path,
+			Timeout: i.timeout,
+		}
+
+		switch i.node.(type) {
+		case PipeGroup:
+			step.Kind = StepGroup
+		case []Pipe, PipeArray:
+			step.Kind = StepArray
+		case TimeoutGroup:
+			step.Kind = StepTimeout
+		case ParallelGroup:
+			step.Kind = StepParallel
+		case IfGroup:
+			step.Kind = StepIf
+		case SwitchGroup:
+			step.Kind = StepSwitch
+		default:
+			step.Kind = StepPipe
+		}
+
+		if step.Kind == StepPipe {
+			info := InfoOf(i.node)
+
+			step.Name = info.Name
+			step.Description = info.Description
+			step.Tags = info.Tags
+		} else {
+			step.Name = step.Kind.String()
+		}
+
+		steps[pc] = step
+	}
+
+	return steps
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockHookKey struct{}
+
+// recordingHook records events as "enter group ", "before [1][0]" etc
+type recordingHook struct {
+	events []string
+}
+
+func (h *recordingHook) EnterGroup(ctx context.Context, group Step) context.Context {
+	h.events = append(h.events, fmt.Sprintf("enter %s%s", group.Name, group.Path))
+
+	return context.WithValue(ctx, mockHookKey{}, group.Path.String())
+}
+
+func (h *recordingHook) ExitGroup(ctx context.Context, group Step, result Result) {
+	h.events = append(h.events, fmt.Sprintf("exit %s%s aborted=%t err=%v", group.Name, group.Path, result.Aborted, result.Err))
+}
+
+func (h *recordingHook) BeforePipe(ctx context.Context, pipe Step) context.Context {
+	h.events = append(h.events, fmt.Sprintf("before %s in %v", pipe.Path, ctx.Value(mockHookKey{})))
+
+	return ctx
+}
+
+func (h *recordingHook) AfterPipe(ctx context.Context, pipe Step, result Result) {
+	h.events = append(h.events, fmt.Sprintf("after %s aborted=%t err=%v", pipe.Path, result.Aborted, result.Err))
+}
+
+func Test_Handler_WithHooks_ExpectEventsInOrder(t *testing.T) {
+	var abortPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, nil
+	}
+
+	hook := &recordingHook{}
+
+	pipes := PipeGroup{
+		[]Pipe{abortPipe, nopPipe},
+		Timeout(time.Minute, PipeGroup{nopPipe}),
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock, WithHooks(hook))
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"enter group",
+		"enter array[0]",
+		"before [0][0] in [0]",
+		"after [0][0] aborted=true err=<nil>",
+		"exit array[0] aborted=true err=<nil>",
+		"enter timeout[1]",
+		"before [1][0] in [1]",
+		"after [1][0] aborted=false err=<nil>",
+		"exit timeout[1] aborted=false err=<nil>",
+		"exit group aborted=false err=<nil>",
+	}, hook.events)
+}
+
+func Test_Handler_WithHooks_PipeFails_ExpectGroupsExitedWithError(t *testing.T) {
+	mockError := errors.New("pipe failed")
+
+	var failingPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, mockError
+	}
+
+	hook := &recordingHook{}
+
+	h, err := New(PipeGroup{PipeGroup{failingPipe}, nopPipe}, mockStruct{}, converterMock, WithHooks(NopHook{}, hook))
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.Equal(t, mockError, err)
+	assert.Equal(t, []string{
+		"enter group",
+		"enter group[0]",
+		"before [0][0] in [0]",
+		"after [0][0] aborted=false err=pipe failed",
+		"exit group[0] aborted=false err=pipe failed",
+		"exit group aborted=false err=pipe failed",
+	}, hook.events)
+}
+
+func Test_Handler_WithHooksAndRecovery_PipePanics_ExpectAfterPipeWithPipeError(t *testing.T) {
+	var panicPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		panic("boom")
+	}
+
+	hook := &recordingHook{}
+
+	h, err := New(PipeGroup{panicPipe}, mockStruct{}, converterMock, WithHooks(hook), WithRecovery())
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	var pipeErr *PipeError
+
+	assert.True(t, errors.As(err, &pipeErr))
+	assert.Equal(t, []string{
+		"enter group",
+		"before [0] in ",
+		"after [0] aborted=false err=" + err.Error(),
+		"exit group aborted=false err=" + err.Error(),
+	}, hook.events)
+}
+
+func Benchmark_Handler_WithNopHook(b *testing.B) {
+	pipes, ctor := benchmarkPipes()
+
+	h, err := New(pipes, ctor, func(f GenericHandlerFunc) interface{} {
+		return f
+	}, WithHooks(NopHook{}))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	handler := h.Handler().(GenericHandlerFunc)
+	ctx := &mockContext{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = handler(ctx)
+	}
+}
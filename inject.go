@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// injectTag is struct tag of services field what overrides injection
+//
+// Example:
+//
+//	type GetArticles struct {
+//		Services struct {
+//			ArticlesRepo
+//			Cache  *Cache       `inject:"articles-cache"`
+//			Logger *slog.Logger `inject:"-"`
+//		}
+//	}
+const injectTag = "inject"
+
+const injectSkip = "-"
+
+// Inject injects services from container to field of handler instance
+// after instance is constructed. Empty field means "Services".
+//
+// If type of field is registered, it's resolved as a whole. Otherwise field should be
+// struct or pointer to struct and each its exported field is resolved by its type,
+// or by name from `inject:"name"` tag, fields tagged with `inject:"-"` are skipped.
+//
+// Container also resolves dependencies of constructor, if WithDependencies
+// is not used. All services are checked by New
+func Inject(c *Container, field string) Option {
+	return func(h *handler) {
+		if field == "" {
+			field = "Services"
+		}
+
+		h.injector = &injector{container: c, field: field}
+
+		if h.resolver == nil {
+			h.resolver = c
+		}
+	}
+}
+
+type injector struct {
+	container *Container
+
+	// name of field
+	field string
+
+	// index of field in handler type
+	index []int
+
+	// whether type of field is registered
+	whole bool
+
+	// struct type of field and whether field is pointer to it
+	t   reflect.Type
+	ptr bool
+
+	fields []injectField
+}
+
+// injectField represents field of services struct
+type injectField struct {
+	index int
+	name  string
+	t     reflect.Type
+}
+
+func (h *handler) initInjector() error {
+	if h.injector == nil {
+		return nil
+	}
+
+	if h.typ == nil {
+		return ErrorInjectUnknownType
+	}
+
+	return h.injector.init(h.typ)
+}
+
+func (i *injector) init(t reflect.Type) error {
+	field, ok := t.FieldByName(i.field)
+
+	if !ok || !field.IsExported() {
+		return fmt.Errorf("%w %s.%s", ErrorInjectField, t, i.field)
+	}
+
+	i.index = field.Index
+
+	if i.container.Has(field.Type) {
+		i.whole = true
+
+		return nil
+	}
+
+	i.t = field.Type
+
+	if i.t.Kind() == reflect.Ptr {
+		i.t = i.t.Elem()
+		i.ptr = true
+	}
+
+	if i.t.Kind() != reflect.Struct {
+		return fmt.Errorf("%w %s.%s", ErrorInjectField, t, i.field)
+	}
+
+	for index := 0; index < i.t.NumField(); index++ {
+		service := i.t.Field(index)
+		name := service.Tag.Get(injectTag)
+
+		if !service.IsExported() || name == injectSkip {
+			continue
+		}
+
+		if err := i.check(name, service.Type); err != nil {
+			return fmt.Errorf("%w: field %s.%s.%s", err, t, i.field, service.Name)
+		}
+
+		i.fields = append(i.fields, injectField{index: index, name: name, t: service.Type})
+	}
+
+	return nil
+}
+
+// check checks that service is registered and assignable to field of type t
+func (i *injector) check(name string, t reflect.Type) error {
+	if name == "" {
+		return i.container.Check(t)
+	}
+
+	if err := i.container.CheckNamed(name); err != nil {
+		return err
+	}
+
+	if serviceType := i.container.namedType(name); !serviceType.AssignableTo(t) {
+		return fmt.Errorf("%w %s to %s", ErrorInjectType, serviceType, t)
+	}
+
+	return nil
+}
+
+// inject resolves services and sets them to field of instance.
+// Struct what is not addressable, like value returned by func() MyHandler
+// constructor, is copied to addressable value what is returned instead of instance
+func (i *injector) inject(ctx context.Context, instance reflect.Value) (reflect.Value, error) {
+	if instance.Kind() == reflect.Struct && !instance.CanAddr() {
+		addressable := reflect.New(instance.Type()).Elem()
+		addressable.Set(instance)
+
+		instance = addressable
+	}
+
+	field := reflect.Indirect(instance).FieldByIndex(i.index)
+
+	if !field.CanSet() {
+		return instance, ErrorInjectNotAddressable
+	}
+
+	if i.whole {
+		value, err := i.container.Resolve(ctx, field.Type())
+		if err != nil {
+			return instance, err
+		}
+
+		field.Set(value)
+
+		return instance, nil
+	}
+
+	target := field
+
+	// pointed struct is shared by copies of instance,
+	// so services are set to new struct for each request
+	if i.ptr {
+		services := reflect.New(i.t)
+
+		if !field.IsNil() {
+			services.Elem().Set(field.Elem())
+		}
+
+		field.Set(services)
+
+		target = services.Elem()
+	}
+
+	for _, service := range i.fields {
+		var value reflect.Value
+		var err error
+
+		if service.name != "" {
+			value, err = i.container.ResolveNamed(ctx, service.name)
+		} else {
+			value, err = i.container.Resolve(ctx, service.t)
+		}
+
+		if err != nil {
+			return instance, err
+		}
+
+		target.Field(service.index).Set(value)
+	}
+
+	return instance, nil
+}
+
+// beginRequest begins request scope of resolvers
+func (h *handler) beginRequest(ctx context.Context) context.Context {
+	if scoper, ok := h.resolver.(RequestScoper); ok {
+		ctx = scoper.BeginRequest(ctx)
+	}
+
+	if h.injector != nil && Resolver(h.injector.container) != h.resolver {
+		ctx = h.injector.container.BeginRequest(ctx)
+	}
+
+	return ctx
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Kind represents category of error returned by pipe or action
+type Kind uint8
+
+const (
+	// KindInternal is unexpected failure, kind of errors what are not *Error
+	KindInternal Kind = iota
+
+	// KindInvalid means request is malformed
+	KindInvalid
+
+	// KindValidation means request is well-formed but its fields are invalid
+	KindValidation
+
+	// KindUnauthorized means request is not authenticated
+	KindUnauthorized
+
+	// KindForbidden means request has no permission
+	KindForbidden
+
+	// KindNotFound means requested resource doesn't exist
+	KindNotFound
+
+	// KindConflict means request conflicts with state of resource
+	KindConflict
+
+	// KindUnavailable means service is temporarily unable to process request
+	KindUnavailable
+
+	// KindTimeout means processing of request has exceeded its deadline
+	KindTimeout
+
+	// KindCanceled means request was canceled by client
+	KindCanceled
+)
+
+// StatusClientClosedRequest is non-standard status of canceled requests
+const StatusClientClosedRequest = 499
+
+func (k Kind) String() string {
+	switch k {
+	case KindInternal:
+		return "internal"
+	case KindInvalid:
+		return "invalid"
+	case KindValidation:
+		return "validation"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindForbidden:
+		return "forbidden"
+	case KindNotFound:
+		return "not found"
+	case KindConflict:
+		return "conflict"
+	case KindUnavailable:
+		return "unavailable"
+	case KindTimeout:
+		return "timeout"
+	case KindCanceled:
+		return "canceled"
+	}
+
+	return "unknown"
+}
+
+// Status returns HTTP status code of kind
+func (k Kind) Status() int {
+	switch k {
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindCanceled:
+		return StatusClientClosedRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
+// FieldError describes invalid field of request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is error of request processing with kind what maps to HTTP status.
+//
+// When pipe returns *Error (or error what wraps it), executor returns
+// its copy annotated with name and path of the pipe, original error
+// is still matched by errors.Is
+//
+// Example:
+//
+//	var ErrorArticleNotFound = handler.NotFound("article not found")
+//
+//	func (action *GetArticle) Action() (interface{}, error) {
+//		article, err := action.Services.Articles.Get(action.Request.ID)
+//		if errors.Is(err, sql.ErrNoRows) {
+//			return nil, ErrorArticleNotFound
+//		}
+//		...
+//	}
+type Error struct {
+	Kind Kind
+
+	// Message is safe to show to client
+	Message string
+
+	// Fields are invalid fields of KindValidation error
+	Fields []FieldError
+
+	// Pipe is name of pipe what returned error, set by executor
+	Pipe string
+
+	// Path is path of pipe what returned error, set by executor
+	Path Path
+
+	// Err is cause of error
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Pipe != "" && e.Err != nil {
+		return "handler: pipe " + e.Pipe + " at " + e.Path.String() + ": " + e.Err.Error()
+	}
+
+	message := "handler: " + e.Kind.String()
+
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewError creates error of kind with message
+func NewError(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// WrapError creates error of kind with message what is caused by err
+func WrapError(kind Kind, err error, message string) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// Invalid creates KindInvalid error
+func Invalid(message string) *Error {
+	return NewError(KindInvalid, message)
+}
+
+// Validation creates KindValidation error with invalid fields
+func Validation(fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Message: "request is invalid", Fields: fields}
+}
+
+// Unauthorized creates KindUnauthorized error
+func Unauthorized(message string) *Error {
+	return NewError(KindUnauthorized, message)
+}
+
+// Forbidden creates KindForbidden error
+func Forbidden(message string) *Error {
+	return NewError(KindForbidden, message)
+}
+
+// NotFound creates KindNotFound error
+func NotFound(message string) *Error {
+	return NewError(KindNotFound, message)
+}
+
+// Conflict creates KindConflict error
+func Conflict(message string) *Error {
+	return NewError(KindConflict, message)
+}
+
+// Unavailable creates KindUnavailable error
+func Unavailable(message string) *Error {
+	return NewError(KindUnavailable, message)
+}
+
+// Internal creates KindInternal error caused by err
+func Internal(err error) *Error {
+	return WrapError(KindInternal, err, "")
+}
+
+// KindOf returns kind of *Error in chain of err.
+// Errors of done context are KindTimeout or KindCanceled,
+// other errors are KindInternal
+func KindOf(err error) Kind {
+	var e *Error
+
+	switch {
+	case errors.As(err, &e):
+		return e.Kind
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	case errors.Is(err, context.Canceled):
+		return KindCanceled
+	}
+
+	return KindInternal
+}
+
+// StatusCode returns HTTP status code of err, see KindOf
+func StatusCode(err error) int {
+	return KindOf(err).Status()
+}
+
+// annotate returns copy of *Error returned by pipe with name and path of the pipe,
+// other errors and already annotated errors are returned as is
+func annotate(err error, i *instruction) error {
+	var e *Error
+
+	if !errors.As(err, &e) || e.Pipe != "" {
+		return err
+	}
+
+	return &Error{
+		Kind:    e.Kind,
+		Message: e.Message,
+		Fields:  e.Fields,
+		Pipe:    pipeName(i.node),
+		Path:    append(Path(nil), i.path...),
+		Err:     err,
+	}
+}
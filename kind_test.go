@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_KindOf_ExpectKindOfErrorInChain(t *testing.T) {
+	assert.Equal(t, KindNotFound, KindOf(fmt.Errorf("loading: %w", NotFound("article not found"))))
+	assert.Equal(t, KindTimeout, KindOf(&ContextError{Err: context.DeadlineExceeded}))
+	assert.Equal(t, KindCanceled, KindOf(context.Canceled))
+	assert.Equal(t, KindInternal, KindOf(errors.New("unexpected")))
+
+	assert.Equal(t, http.StatusConflict, StatusCode(Conflict("already exists")))
+	assert.Equal(t, http.StatusUnprocessableEntity, StatusCode(Validation(FieldError{Field: "title", Message: "required"})))
+	assert.Equal(t, http.StatusInternalServerError, StatusCode(errors.New("unexpected")))
+}
+
+func Test_Error_ExpectMessageAndCause(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	err := WrapError(KindUnavailable, cause, "database is unavailable")
+
+	assert.Equal(t, "handler: unavailable: database is unavailable: connection refused", err.Error())
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, "handler: internal: connection refused", Internal(cause).Error())
+}
+
+func Test_Handler_PipeReturnsTypedError_ExpectAnnotatedWithPipe(t *testing.T) {
+	notFound := NotFound("article not found")
+
+	failingPipe := Named("LoadArticle", func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, notFound
+	})
+
+	h, err := New(PipeGroup{[]Pipe{nopPipe}, PipeGroup{failingPipe}}, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	var e *Error
+
+	assert.True(t, errors.As(err, &e))
+	assert.True(t, errors.Is(err, notFound))
+	assert.Equal(t, "LoadArticle", e.Pipe)
+	assert.Equal(t, Path{1, 0}, e.Path)
+	assert.Equal(t, KindNotFound, e.Kind)
+	assert.Equal(t, "handler: pipe LoadArticle at [1][0]: handler: not found: article not found", err.Error())
+	assert.Empty(t, notFound.Pipe, "original error is not modified")
+}
+
+func Test_Handler_PipeReturnsPlainError_ExpectReturnedAsIs(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		kind Kind
+	}{
+		"plain":    {errors.New("connection refused"), KindInternal},
+		"deadline": {fmt.Errorf("loading: %w", context.DeadlineExceeded), KindTimeout},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			failingPipe := Named("LoadArticle", func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+				return AbortPipeGroup, c.err
+			})
+
+			h, err := New(PipeGroup{PipeArray{nopPipe, failingPipe}}, mockStruct{}, converterMock)
+			assert.NoError(t, err)
+
+			err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+			assert.Equal(t, c.err, err, "error of other frameworks is matched by type assertion")
+			assert.Equal(t, c.kind, KindOf(err))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// LogLevels configures levels of records written by handler logger.
+// Nil levels are replaced with defaults
+type LogLevels struct {
+	// Error is level of errors returned by pipes, slog.LevelError by default
+	Error slog.Leveler
+
+	// Abort is level of pipes what returned AbortPipeGroup, slog.LevelDebug by default
+	Abort slog.Leveler
+
+	// Panic is level of panics recovered by WithRecovery, slog.LevelError by default
+	Panic slog.Leveler
+}
+
+// loggerKey is key of request-scoped logger in context
+type loggerKey struct{}
+
+// Logger returns logger of request passed to ContextPipe by handler
+// created with WithLogger, or slog.Default() if there is no such logger.
+// Logger has handler type, pipe name and pipe path attributes
+//
+// Example:
+//
+//	var LoadArticles handler.ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+//		handler.Logger(ctx).Info("loading articles")
+//		...
+//	}
+func Logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// WithLogger makes handler provide request-scoped logger to pipes, see Logger,
+// and log errors, aborts and recovered panics of pipes with handler type and pipe path.
+// slog.Default() is used if logger is nil
+//
+// Example:
+//
+//	handler.New(pipes, action.GetArticles{}, nethttp.Converter,
+//		handler.WithLogger(logger, handler.LogLevels{Abort: slog.LevelInfo}))
+func WithLogger(logger *slog.Logger, levels LogLevels) Option {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	if levels.Error == nil {
+		levels.Error = slog.LevelError
+	}
+
+	if levels.Abort == nil {
+		levels.Abort = slog.LevelDebug
+	}
+
+	if levels.Panic == nil {
+		levels.Panic = slog.LevelError
+	}
+
+	return WithHooks(&loggingHook{logger: logger, levels: levels})
+}
+
+// loggingHook puts logger into context of groups and pipes and logs results of pipes
+type loggingHook struct {
+	logger *slog.Logger
+	levels LogLevels
+}
+
+// requestLog holds error what was logged during request,
+// so it isn't logged again when it exits the root group.
+// Pipes of parallel branches log concurrently, so it's guarded by mutex
+type requestLog struct {
+	mu     sync.Mutex
+	logged error
+}
+
+func (l *requestLog) set(err error) {
+	l.mu.Lock()
+	l.logged = err
+	l.mu.Unlock()
+}
+
+func (l *requestLog) has(err error) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.logged == err
+}
+
+// requestLogKey is key of *requestLog in context
+type requestLogKey struct{}
+
+func (l *loggingHook) EnterGroup(ctx context.Context, group Step) context.Context {
+	if len(group.Path) > 0 {
+		return ctx
+	}
+
+	ctx = context.WithValue(ctx, requestLogKey{}, &requestLog{})
+
+	return context.WithValue(ctx, loggerKey{}, l.logger.With(slog.String("handler", typeName(group.Handler))))
+}
+
+func (l *loggingHook) ExitGroup(ctx context.Context, group Step, result Result) {
+	if len(group.Path) > 0 || result.Err == nil {
+		return
+	}
+
+	if log, ok := ctx.Value(requestLogKey{}).(*requestLog); ok && log.has(result.Err) {
+		return
+	}
+
+	Logger(ctx).LogAttrs(ctx, l.levels.Error.Level(), "handler failed",
+		slog.Duration("duration", result.Duration),
+		slog.Any("error", result.Err),
+	)
+}
+
+func (l *loggingHook) BeforePipe(ctx context.Context, pipe Step) context.Context {
+	return context.WithValue(ctx, loggerKey{}, Logger(ctx).With(
+		slog.String("pipe", pipe.Name),
+		slog.String("path", pipe.Path.String()),
+	))
+}
+
+func (l *loggingHook) AfterPipe(ctx context.Context, pipe Step, result Result) {
+	logger := Logger(ctx)
+
+	var pipeErr *PipeError
+
+	switch {
+	case errors.As(result.Err, &pipeErr):
+		logger.LogAttrs(ctx, l.levels.Panic.Level(), "pipe panicked",
+			slog.Duration("duration", result.Duration),
+			slog.Any("panic", pipeErr.Recovered),
+			slog.String("stack", string(pipeErr.Stack)),
+		)
+	case result.Err != nil:
+		logger.LogAttrs(ctx, l.levels.Error.Level(), "pipe failed",
+			slog.Duration("duration", result.Duration),
+			slog.Any("error", result.Err),
+		)
+	case result.Aborted:
+		logger.LogAttrs(ctx, l.levels.Abort.Level(), "pipe aborted",
+			slog.Duration("duration", result.Duration),
+		)
+
+		return
+	default:
+		return
+	}
+
+	if log, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		log.set(result.Err)
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLogger returns logger what writes records without time, duration and stack
+func newTestLogger(b *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(b, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey, "duration", "stack":
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+}
+
+func Test_Handler_WithLogger_ExpectErrorsAndAbortsLogged(t *testing.T) {
+	var b bytes.Buffer
+
+	var abortPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, nil
+	}
+
+	var failingPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, errors.New("failed")
+	}
+
+	pipes := PipeGroup{
+		PipeArray{Named("bind", nopPipe), Named("abort", abortPipe)},
+		Named("fail", failingPipe),
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock, WithLogger(newTestLogger(&b), LogLevels{Abort: slog.LevelInfo}))
+	assert.NoError(t, err)
+
+	assert.Error(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+
+	assert.Equal(t, []string{
+		`level=INFO msg="pipe aborted" handler=handler.mockStruct pipe=abort path=[0][1]`,
+		`level=ERROR msg="pipe failed" handler=handler.mockStruct pipe=fail path=[1] error=failed`,
+	}, strings.Split(strings.TrimSpace(b.String()), "\n"))
+}
+
+func Test_Handler_WithLogger_PipePanics_ExpectPanicLogged(t *testing.T) {
+	var b bytes.Buffer
+
+	panicPipe := Named("explode", func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		panic("boom")
+	})
+
+	h, err := New(PipeGroup{panicPipe}, mockStruct{}, converterMock, WithRecovery(), WithLogger(newTestLogger(&b), LogLevels{}))
+	assert.NoError(t, err)
+
+	assert.Error(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+
+	assert.Equal(t, `level=ERROR msg="pipe panicked" handler=handler.mockStruct pipe=explode path=[0] panic=boom`+"\n", b.String())
+}
+
+func Test_Handler_WithLogger_ContextPipe_ExpectRequestLogger(t *testing.T) {
+	var b bytes.Buffer
+
+	pipe := NamedContext("load", func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		Logger(ctx).Info("loading")
+
+		return ContinuePipeGroup(v), nil
+	})
+
+	h, err := New(PipeGroup{pipe}, mockStruct{}, converterMock, WithLogger(newTestLogger(&b), LogLevels{}))
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+
+	assert.Equal(t, `level=INFO msg=loading handler=handler.mockStruct pipe=load path=[0]`+"\n", b.String())
+}
+
+func Test_Handler_WithLogger_TimeoutExceeded_ExpectHandlerFailureLogged(t *testing.T) {
+	var b bytes.Buffer
+
+	var slowPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		time.Sleep(10 * time.Millisecond)
+
+		return &v, nil
+	}
+
+	pipes := PipeGroup{Timeout(time.Millisecond, PipeGroup{Named("slow", slowPipe), nopPipe})}
+
+	h, err := New(pipes, mockStruct{}, converterMock, WithLogger(newTestLogger(&b), LogLevels{Error: slog.LevelWarn}))
+	assert.NoError(t, err)
+
+	assert.Error(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+
+	assert.Equal(t, `level=WARN msg="handler failed" handler=handler.mockStruct error="handler: execution aborted: context deadline exceeded"`+"\n", b.String())
+}
+
+func Test_Logger_NoLogger_ExpectDefault(t *testing.T) {
+	assert.Equal(t, slog.Default(), Logger(context.Background()))
+}
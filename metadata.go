@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Endpoint is a marker field what declares route metadata of handler type
+// with `method`, `path`, `summary`, `description` and `scope` tags.
+// Scopes are separated by spaces or commas
+//
+// Example:
+//
+//	type GetArticle struct {
+//		handler.Endpoint `method:"GET" path:"/articles/:id" summary:"Get article" scope:"articles:read"`
+//
+//		Request struct {
+//			ID int `param:"id"`
+//		}
+//	}
+//
+// Marker may also be declared as blank field:
+//
+//	_ handler.Endpoint `method:"GET" path:"/articles/:id"`
+type Endpoint struct{}
+
+var endpointType = reflect.TypeOf(Endpoint{})
+
+// Metadata represents route metadata declared by Endpoint marker field
+type Metadata struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+
+	// Scopes required to call handler
+	Scopes []string
+}
+
+// metadataResult is cached result of MetadataOf
+type metadataResult struct {
+	metadata Metadata
+	err      error
+}
+
+var metadataCache sync.Map
+
+// MetadataOf returns metadata declared by Endpoint field of struct type t,
+// zero Metadata if there is no such field. Result is cached
+func MetadataOf(t reflect.Type) (Metadata, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if cached, ok := metadataCache.Load(t); ok {
+		result := cached.(metadataResult)
+
+		return result.metadata, result.err
+	}
+
+	metadata, err := metadataOf(t)
+
+	metadataCache.Store(t, metadataResult{metadata: metadata, err: err})
+
+	return metadata, err
+}
+
+func metadataOf(t reflect.Type) (Metadata, error) {
+	if t.Kind() != reflect.Struct {
+		return Metadata{}, nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Type != endpointType {
+			continue
+		}
+
+		metadata := Metadata{
+			Method:      strings.ToUpper(field.Tag.Get("method")),
+			Path:        field.Tag.Get("path"),
+			Summary:     field.Tag.Get("summary"),
+			Description: field.Tag.Get("description"),
+			Scopes: strings.FieldsFunc(field.Tag.Get("scope"), func(r rune) bool {
+				return r == ' ' || r == ','
+			}),
+		}
+
+		for _, r := range metadata.Method {
+			if r < 'A' || r > 'Z' {
+				return Metadata{}, fmt.Errorf("%w %q of %s", ErrorEndpointMethod, metadata.Method, t)
+			}
+		}
+
+		if metadata.Path != "" && !strings.HasPrefix(metadata.Path, "/") {
+			return Metadata{}, fmt.Errorf("%w %q of %s", ErrorEndpointPath, metadata.Path, t)
+		}
+
+		return metadata, nil
+	}
+
+	return Metadata{}, nil
+}
+
+// Metadata returns route metadata declared by Endpoint field of handler type
+func (h *handler) Metadata() Metadata {
+	return h.metadata
+}
+
+// Type returns struct type of handler instance,
+// nil if type of instance created by func() reflect.Value isn't declared by WithType
+func (h *handler) Type() reflect.Type {
+	return h.typ
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	mockEndpoint struct {
+		Endpoint `method:"get" path:"/articles/:id" summary:"Get article" description:"Returns article by id" scope:"articles:read, admin"`
+
+		Field1 string
+	}
+
+	mockBlankEndpoint struct {
+		_ Endpoint `method:"POST" path:"/articles"`
+	}
+
+	mockInvalidEndpoint struct {
+		Endpoint `path:"articles"`
+	}
+)
+
+func Test_New_Endpoint_ExpectMetadataExposedByHandler(t *testing.T) {
+	h, err := New(mockPipes, &mockEndpoint{}, converterMock)
+	assert.NoError(t, err)
+
+	var handler Handler = h
+
+	assert.Equal(t, Metadata{
+		Method:      "GET",
+		Path:        "/articles/:id",
+		Summary:     "Get article",
+		Description: "Returns article by id",
+		Scopes:      []string{"articles:read", "admin"},
+	}, handler.Metadata())
+	assert.Equal(t, reflect.TypeOf(mockEndpoint{}), handler.Type())
+
+	h, err = New(mockPipes, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+	assert.Equal(t, Metadata{}, h.Metadata())
+}
+
+func Test_New_InvalidEndpoint_ExpectError(t *testing.T) {
+	_, err := New(mockPipes, mockInvalidEndpoint{}, converterMock)
+
+	assert.True(t, errors.Is(err, ErrorEndpointPath))
+}
+
+func Test_Router_Register_ExpectRoutesFromEndpoints(t *testing.T) {
+	router := NewRouter(mockPipes, converterMock)
+
+	assert.NoError(t, router.Register(mockEndpoint{}, mockBlankEndpoint{}))
+
+	routes := router.Routes()
+
+	assert.Equal(t, "GET /articles/:id", routes[0].String())
+	assert.Equal(t, "POST /articles", routes[1].String())
+
+	err := router.Register(mockStruct{})
+
+	assert.True(t, errors.Is(err, ErrorRouteInvalid))
+	assert.Contains(t, err.Error(), "handler.mockStruct")
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Outcome represents outcome of handler or pipe execution
+type Outcome uint8
+
+const (
+	// OutcomeOK means pipe returned value or handler finished all pipes
+	OutcomeOK Outcome = iota
+
+	// OutcomeAborted means pipe returned AbortPipeGroup
+	OutcomeAborted
+
+	// OutcomeError means pipe or handler returned error
+	OutcomeError
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeOK:
+		return "ok"
+	case OutcomeAborted:
+		return "aborted"
+	case OutcomeError:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+// MetricsCollector collects metrics of handlers and their pipes.
+// Handler is name of handler type, pipe is name of pipe, see Named
+type MetricsCollector interface {
+	ObserveHandler(handler string, duration time.Duration, outcome Outcome)
+	ObservePipe(handler, pipe string, duration time.Duration, outcome Outcome)
+}
+
+// WithMetrics makes handler report duration and outcome
+// of its execution and of each pipe to collector
+//
+// Example:
+//
+//	metrics := prommetrics.New(prommetrics.Config{})
+//	http.Handle("/metrics", metrics)
+//
+//	handler.New(pipes, action.GetArticles{}, nethttp.Converter, handler.WithMetrics(metrics))
+func WithMetrics(collector MetricsCollector) Option {
+	return WithHooks(metricsHook{collector: collector})
+}
+
+// metricsHook reports results of steps to collector
+type metricsHook struct {
+	NopHook
+
+	collector MetricsCollector
+}
+
+func (m metricsHook) ExitGroup(ctx context.Context, group Step, result Result) {
+	// root group is the whole handler
+	if len(group.Path) == 0 {
+		m.collector.ObserveHandler(typeName(group.Handler), result.Duration, result.Outcome())
+	}
+}
+
+func (m metricsHook) AfterPipe(ctx context.Context, pipe Step, result Result) {
+	m.collector.ObservePipe(typeName(pipe.Handler), pipe.Name, result.Duration, result.Outcome())
+}
+
+// Outcome returns outcome of pipe or group execution
+func (r Result) Outcome() Outcome {
+	switch {
+	case r.Err != nil:
+		return OutcomeError
+	case r.Aborted:
+		return OutcomeAborted
+	}
+
+	return OutcomeOK
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "unknown"
+	}
+
+	return t.String()
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCollector struct {
+	observed []string
+}
+
+func (c *mockCollector) ObserveHandler(handler string, duration time.Duration, outcome Outcome) {
+	c.observed = append(c.observed, fmt.Sprintf("%s %s", handler, outcome))
+}
+
+func (c *mockCollector) ObservePipe(handler, pipe string, duration time.Duration, outcome Outcome) {
+	c.observed = append(c.observed, fmt.Sprintf("%s %s %s", handler, pipe, outcome))
+}
+
+func Test_Handler_WithMetrics_ExpectOutcomesPerPipe(t *testing.T) {
+	var abortPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, nil
+	}
+
+	var failingPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, errors.New("failed")
+	}
+
+	collector := &mockCollector{}
+
+	pipes := PipeGroup{
+		PipeArray{Named("bind", nopPipe), Named("abort", abortPipe)},
+		Named("fail", failingPipe),
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock, WithMetrics(collector))
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{
+		"handler.mockStruct bind ok",
+		"handler.mockStruct abort aborted",
+		"handler.mockStruct fail error",
+		"handler.mockStruct error",
+	}, collector.observed)
+}
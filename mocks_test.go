@@ -5,7 +5,7 @@ import (
 )
 
 var (
-	pipesMock = PipeGroup{[]Pipe{}}
+	pipesMock = PipeGroup{[]Pipe{nopPipe}}
 
 	tMock struct{}
 
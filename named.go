@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"strings"
+)
+
+// PipeInfo describes pipe in errors, hooks and dumps
+type PipeInfo struct {
+	Name        string
+	Description string
+	Tags        []string
+}
+
+// String returns name of pipe with tags, like BindRequest [http request]
+func (i PipeInfo) String() string {
+	if len(i.Tags) == 0 {
+		return i.Name
+	}
+
+	return i.Name + " [" + strings.Join(i.Tags, " ") + "]"
+}
+
+// PipeOption configures info of named pipe
+type PipeOption func(info *PipeInfo)
+
+// Description sets description of named pipe
+func Description(description string) PipeOption {
+	return func(info *PipeInfo) {
+		info.Description = description
+	}
+}
+
+// Tags adds tags to named pipe
+func Tags(tags ...string) PipeOption {
+	return func(info *PipeInfo) {
+		info.Tags = append(info.Tags, tags...)
+	}
+}
+
+// NamedPipe is pipe with name, description and tags, it's placed in PipeGroup
+// or PipeArray like other pipes. Name is used by hooks, metrics, errors and dumps
+// instead of name of pipe func
+type NamedPipe struct {
+	PipeInfo
+
+	// Pipe is Pipe or ContextPipe
+	Pipe interface{}
+}
+
+// Named returns pipe with name, description and tags
+//
+// Example:
+//
+//	var BindRequestPipe = handler.Named("BindRequest", func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+//		...
+//	}, handler.Description("binds request body to Request field"), handler.Tags("http"))
+func Named(name string, pipe Pipe, options ...PipeOption) NamedPipe {
+	return NamedPipe{PipeInfo: newPipeInfo(name, options), Pipe: pipe}
+}
+
+// NamedContext is Named for ContextPipe
+func NamedContext(name string, pipe ContextPipe, options ...PipeOption) NamedPipe {
+	return NamedPipe{PipeInfo: newPipeInfo(name, options), Pipe: pipe}
+}
+
+func newPipeInfo(name string, options []PipeOption) PipeInfo {
+	info := PipeInfo{Name: name}
+
+	for _, option := range options {
+		option(&info)
+	}
+
+	return info
+}
+
+// String returns name of pipe
+func (p Pipe) String() string {
+	return pipeName(p)
+}
+
+// String returns name of pipe
+func (p ContextPipe) String() string {
+	return pipeName(p)
+}
+
+// InfoOf returns info of pipe created by Named or NamedContext,
+// or of predicate created by NamedPredicate.
+// Info of other pipes contains only name of pipe func
+func InfoOf(pipe interface{}) PipeInfo {
+	switch p := pipe.(type) {
+	case NamedPipe:
+		return p.PipeInfo
+	case NamedCondition:
+		return p.PipeInfo
+	}
+
+	return PipeInfo{Name: pipeName(pipe)}
+}
+
+// validateNamed checks pipe of NamedPipe
+func validateNamed(pipe NamedPipe, path Path) (NamedPipe, error) {
+	validated, err := validatePipe(pipe.Pipe, path)
+	if err != nil {
+		return NamedPipe{}, err
+	}
+
+	switch validated.(type) {
+	case Pipe, ContextPipe:
+		pipe.Pipe = validated
+
+		return pipe, nil
+	}
+
+	return NamedPipe{}, &TreeError{Path: path, Value: pipe.Pipe, Err: ErrorUnsupportedPipeType}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Named_ExpectInfoOfSameFunc(t *testing.T) {
+	bind := Named("bind", nopPipe)
+	validate := Named("validate", nopPipe, Description("validates request"), Tags("http"))
+
+	assert.Equal(t, PipeInfo{Name: "bind"}, InfoOf(bind))
+	assert.Equal(t, PipeInfo{Name: "validate", Description: "validates request", Tags: []string{"http"}}, InfoOf(validate))
+
+	assert.Equal(t, "bind", bind.String())
+	assert.Equal(t, "validate [http]", fmt.Sprint(validate))
+
+	assert.Equal(t, PipeInfo{Name: pipeName(nopPipe)}, InfoOf(nopPipe))
+	assert.Equal(t, PipeInfo{Name: "IsForm"}, InfoOf(NamedPredicate("IsForm", isForm)))
+}
+
+func Test_New_NamedPipeInvalid_ExpectError(t *testing.T) {
+	cases := map[string]struct {
+		pipes PipeGroup
+		err   error
+		path  Path
+	}{
+		"nil pipe":            {PipeGroup{Named("bind", nil)}, ErrorPipeNil, Path{0}},
+		"group as pipe":       {PipeGroup{NamedPipe{PipeInfo: PipeInfo{Name: "group"}, Pipe: PipeGroup{nopPipe}}}, ErrorUnsupportedPipeType, Path{0}},
+		"empty array":         {PipeGroup{PipeArray{}}, ErrorPipeGroupEmpty, Path{0}},
+		"group in array":      {PipeGroup{PipeArray{nopPipe, PipeGroup{nopPipe}}}, ErrorUnsupportedPipeType, Path{0, 1}},
+		"nil in array":        {PipeGroup{PipeArray{Named("bind", nil)}}, ErrorPipeNil, Path{0, 0}},
+		"nil named predicate": {PipeGroup{If(NamedPredicate("IsForm", nil), PipeGroup{nopPipe}, nil)}, ErrorPredicateNil, Path{0}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(c.pipes, mockStruct{}, converterMock)
+
+			var treeErr *TreeError
+
+			assert.ErrorIs(t, err, c.err)
+			assert.ErrorAs(t, err, &treeErr)
+			assert.Equal(t, c.path, treeErr.Path)
+		})
+	}
+}
+
+func Test_Handler_PipeArray_ExpectContextPipesAndArraySemantics(t *testing.T) {
+	var steps []Step
+
+	hook := &stepHook{steps: &steps}
+
+	var abort ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, nil
+	}
+
+	pipes := PipeGroup{
+		PipeArray{Named("bind", nopPipe), NamedContext("abort", abort), Named("skipped", nopPipe)},
+		Named("call", nopPipe),
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock, WithHooks(hook))
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+
+	var executed []string
+
+	for _, step := range steps {
+		executed = append(executed, step.Name)
+	}
+
+	assert.Equal(t, []string{"bind", "abort", "call"}, executed, "aborted pipe skips the rest of array")
+}
+
+func Test_NamedContext_ExpectNamedAndCalled(t *testing.T) {
+	called := false
+
+	pipe := NamedContext("load", func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		called = true
+
+		return ContinuePipeGroup(v), nil
+	}, Tags("db"))
+
+	h, err := New(PipeGroup{[]Pipe{nopPipe}, PipeGroup{pipe}}, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+	assert.True(t, called)
+	assert.Equal(t, "load [db]", InfoOf(pipe).String())
+}
+
+func Test_Handler_NamedPipePanics_ExpectNameInPipeError(t *testing.T) {
+	panicPipe := Named("Explode", func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		panic("pipe panicked")
+	})
+
+	h, err := New(PipeGroup{PipeArray{nopPipe, panicPipe}}, mockStruct{}, converterMock, WithRecovery())
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	var pipeError *PipeError
+
+	assert.True(t, errors.As(err, &pipeError))
+	assert.Equal(t, "handler: pipe Explode at [0][1] panicked: pipe panicked", pipeError.Error())
+}
+
+func Test_Handler_WithHooks_NamedPipe_ExpectStepInfo(t *testing.T) {
+	var steps []Step
+
+	hook := &stepHook{steps: &steps}
+
+	h, err := New(PipeGroup{Named("bind", nopPipe, Description("binds"), Tags("http"))}, mockStruct{}, converterMock, WithHooks(hook))
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+
+	assert.Len(t, steps, 1)
+	assert.Equal(t, "bind", steps[0].Name)
+	assert.Equal(t, "binds", steps[0].Description)
+	assert.Equal(t, []string{"http"}, steps[0].Tags)
+}
+
+// stepHook records steps of pipes
+type stepHook struct {
+	NopHook
+
+	steps *[]Step
+}
+
+func (h *stepHook) BeforePipe(ctx context.Context, pipe Step) context.Context {
+	*h.steps = append(*h.steps, pipe)
+
+	return ctx
+}
@@ -0,0 +1,151 @@
+package nethttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/pipes"
+)
+
+var ErrorResponseWritten = fmt.Errorf("nethttp: response is already written")
+
+var (
+	_ pipes.Adapter         = Adapter{}
+	_ pipes.ProblemAdapter  = Adapter{}
+	_ handler.ProblemWriter = Adapter{}
+	_ pipes.Adapter         = FormAdapter{}
+)
+
+// Problems makes handler write errors as problem details, see handler.WithProblems
+//
+// Example:
+//
+//	h, err := handler.New(nethttp.Pipes, action.GetArticle{}, nethttp.Converter, nethttp.Problems)
+var Problems = handler.WithProblems(handler.ProblemConfig{Writer: Adapter{}})
+
+// Adapter adapts standard pipes to net/http.
+// Expects http.ResponseWriter and *http.Request as arguments
+type Adapter struct{}
+
+// Bind decodes JSON body to dst, then sets fields of dst
+// tagged with `query:"name"` from URL query
+func (Adapter) Bind(dst interface{}, args ...interface{}) error {
+	_, r := request(args)
+
+	if r.Body != nil && r.Body != http.NoBody {
+		err := json.NewDecoder(r.Body).Decode(dst)
+
+		if err != nil && err != io.EOF {
+			return err
+		}
+	}
+
+	return bindValues(dst, "query", r.URL.Query())
+}
+
+// FormAdapter is Adapter what binds url-encoded or multipart form instead of JSON body
+type FormAdapter struct {
+	Adapter
+}
+
+// maxFormMemory is memory limit of multipart form, the rest of files is stored on disk
+const maxFormMemory = 32 << 20
+
+// Bind parses form, then sets fields of dst tagged with `form:"name"`
+// from form values and fields tagged with `query:"name"` from URL query
+func (FormAdapter) Bind(dst interface{}, args ...interface{}) error {
+	_, r := request(args)
+
+	err := r.ParseMultipartForm(maxFormMemory)
+	if err != nil && err != http.ErrNotMultipart {
+		return err
+	}
+
+	if err := bindValues(dst, "form", r.Form); err != nil {
+		return err
+	}
+
+	return bindValues(dst, "query", r.URL.Query())
+}
+
+// bindValues sets fields of dst tagged with tag from values
+func bindValues(dst interface{}, tag string, values url.Values) error {
+	v := reflect.Indirect(reflect.ValueOf(dst))
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+
+		name, ok := field.Tag.Lookup(tag)
+		if !ok || !field.IsExported() || !values.Has(name) {
+			continue
+		}
+
+		if err := pipes.SetString(v.Field(i), values.Get(name)); err != nil {
+			return fmt.Errorf("%s %s: %w", tag, name, err)
+		}
+	}
+
+	return nil
+}
+
+// JSON writes v as JSON with status
+func (Adapter) JSON(status int, v interface{}, args ...interface{}) error {
+	w, _ := request(args)
+
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Problem writes problem details with application/problem+json content type.
+// Instance is set to request URI if empty.
+// ErrorResponseWritten is returned if response was already written by pipes
+func (Adapter) Problem(problem *handler.Problem, args ...interface{}) error {
+	w, r := request(args)
+
+	if rw, ok := w.(*responseWriter); ok && rw.written {
+		return ErrorResponseWritten
+	}
+
+	if problem.Instance == "" {
+		problem.Instance = r.URL.RequestURI()
+	}
+
+	w.Header().Set("Content-Type", handler.ProblemContentType)
+	w.WriteHeader(problem.Status)
+
+	return json.NewEncoder(w).Encode(problem)
+}
+
+// Param returns path wildcard of http.ServeMux pattern
+func (Adapter) Param(name string, args ...interface{}) string {
+	_, r := request(args)
+
+	return r.PathValue(name)
+}
+
+// request extracts net/http arguments passed by Converter
+func request(args []interface{}) (http.ResponseWriter, *http.Request) {
+	const (
+		writerArg  = 0
+		requestArg = 1
+	)
+
+	return args[writerArg].(http.ResponseWriter), args[requestArg].(*http.Request)
+}
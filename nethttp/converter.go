@@ -0,0 +1,53 @@
+// Package nethttp provides converter and standard pipes
+// for using handlers with net/http
+package nethttp
+
+import (
+	"net/http"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+// Converter converts generic handler to http.HandlerFunc.
+// Generic handler receives http.ResponseWriter and *http.Request as arguments.
+//
+// If handler returns error and nothing was written to response yet,
+// status 500 is written with its status text, text of error isn't sent to client
+//
+// Example:
+//
+//	h, err := handler.New(nethttp.Pipes, action.GetArticles{}, nethttp.Converter)
+//	http.Handle("/articles", h.Handler().(http.HandlerFunc))
+var Converter handler.Converter = func(f handler.GenericHandlerFunc) interface{} {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			rw := &responseWriter{ResponseWriter: w}
+
+			if err := f(rw, r); err != nil && !rw.written {
+				http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		},
+	)
+}
+
+// responseWriter remembers if anything was written to response
+type responseWriter struct {
+	http.ResponseWriter
+
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns original http.ResponseWriter, used by http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,54 @@
+package nethttp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+var ErrorNotHTTPHandler = fmt.Errorf("nethttp: mounted handler is not http.Handler")
+
+// Mounter mounts handlers of handler.Router to mux.
+// Path parameters like /articles/:id are converted to wildcards of http.ServeMux
+//
+// Example:
+//
+//	router := handler.NewRouter(nethttp.Pipes, nethttp.Converter)
+//	router.Handle(handler.Route{Method: "GET", Path: "/articles/:id", T: action.GetArticle{}})
+//
+//	mux := http.NewServeMux()
+//	err := router.Mount(nethttp.Mounter(mux))
+func Mounter(mux *http.ServeMux) handler.Mounter {
+	return handler.MounterFunc(func(method, path string, h interface{}) (err error) {
+		httpHandler, ok := h.(http.Handler)
+		if !ok {
+			return ErrorNotHTTPHandler
+		}
+
+		// http.ServeMux panics on conflicting patterns
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("nethttp: %v", r)
+			}
+		}()
+
+		mux.Handle(method+" "+Pattern(path), httpHandler)
+
+		return nil
+	})
+}
+
+// Pattern converts path parameters like :id to wildcards of http.ServeMux
+func Pattern(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
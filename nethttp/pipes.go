@@ -0,0 +1,40 @@
+package nethttp
+
+import (
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/pipes"
+)
+
+// StandardPipes are standard pipes what use net/http Adapter
+var StandardPipes = pipes.New(Adapter{}, pipes.Config{})
+
+// Pipes is a standard pipe group for net/http handlers.
+// Binds and validates "Request" field, calls "Action" method and
+// renders its result or "Response" field as JSON.
+//
+// Bind and validation pipes are placed directly in group,
+// so their failure stops execution of the whole group
+var Pipes = StandardPipes.Group()
+
+// FormPipes are standard pipes what use net/http FormAdapter
+var FormPipes = pipes.New(FormAdapter{}, pipes.Config{})
+
+var (
+	// BindRequestPipe binds JSON body, URL query and path wildcards to "Request" field
+	BindRequestPipe handler.NamedPipe = StandardPipes.BindRequest
+
+	// BindFormPipe binds form values, URL query and path wildcards to "Request" field,
+	// fields are set from values by `form:"name"` tag
+	BindFormPipe = handler.Named("BindForm", FormPipes.BindRequest.Pipe.(handler.Pipe),
+		handler.Description("binds form to Request field"), handler.Tags("http"))
+
+	// ValidateRequestPipe validates "Request" field if it implements pipes.Validator
+	ValidateRequestPipe handler.NamedPipe = StandardPipes.ValidateRequest
+
+	// CallActionPipe calls "Action() (interface{}, error)" method of handler
+	// and writes its result
+	CallActionPipe handler.NamedPipe = StandardPipes.CallAction
+
+	// RenderPipe writes "Response" field of handler as JSON
+	RenderPipe handler.NamedPipe = StandardPipes.Render
+)
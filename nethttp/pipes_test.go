@@ -0,0 +1,292 @@
+package nethttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/pipes"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	mockRequest struct {
+		ID     int    `json:"-" param:"id"`
+		Search string `json:"-" query:"search"`
+		Title  string `json:"title" form:"title"`
+	}
+
+	mockAction struct {
+		Request mockRequest
+	}
+
+	mockResponseAction struct {
+		Response struct {
+			Title string `json:"title"`
+		}
+	}
+
+	mockFailingAction struct{}
+)
+
+func (r *mockRequest) Validate() error {
+	if r.Title == "invalid" {
+		return errors.New("invalid title")
+	}
+
+	return nil
+}
+
+func (a mockAction) Action() (interface{}, error) {
+	return a.Request, nil
+}
+
+func (a mockFailingAction) Action() (interface{}, error) {
+	return nil, errors.New("action failed")
+}
+
+func serve(t *testing.T, h interface{}, pattern string, r *http.Request) *httptest.ResponseRecorder {
+	handle, err := handler.New(Pipes, h, Converter)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle(pattern, handle.Handler().(http.HandlerFunc))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	return w
+}
+
+func Test_Pipes_BindRequest_ExpectBodyQueryAndParamsBound(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/articles/42?search=go", strings.NewReader(`{"title":"hello"}`))
+
+	w := serve(t, mockAction{}, "POST /articles/{id}", r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"hello"}`, w.Body.String())
+}
+
+func Test_Pipes_BindRequest_ExpectParamBound(t *testing.T) {
+	var bound mockRequest
+
+	var capture handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		bound = v.FieldByName("Request").Interface().(mockRequest)
+
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	h, err := handler.New(handler.PipeGroup{handler.PipeArray{BindRequestPipe, capture}}, mockAction{}, Converter)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /articles/{id}", h.Handler().(http.HandlerFunc))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/articles/42?search=go", nil))
+
+	assert.Equal(t, mockRequest{ID: 42, Search: "go"}, bound)
+}
+
+func Test_Pipes_BindRequest_InvalidParam_ExpectBadRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/articles/abc", nil)
+
+	w := serve(t, mockAction{}, "GET /articles/{id}", r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func Test_Pipes_ValidateRequest_ExpectBadRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/articles/1", strings.NewReader(`{"title":"invalid"}`))
+
+	w := serve(t, mockAction{}, "POST /articles/{id}", r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"invalid title"}`, w.Body.String())
+}
+
+func Test_Pipes_CallAction_ActionError_ExpectInternalServerError(t *testing.T) {
+	w := serve(t, mockFailingAction{}, "/", httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "action failed")
+}
+
+func Test_Pipes_Render_NoAction_ExpectResponseField(t *testing.T) {
+	action := mockResponseAction{}
+	action.Response.Title = "rendered"
+
+	w := serve(t, action, "/", httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"title":"rendered"}`, w.Body.String())
+}
+
+func Test_Pipes_Render_NoActionNoResponse_ExpectNoContent(t *testing.T) {
+	w := serve(t, struct{}{}, "/", httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func Test_Converter_HandlerError_ExpectInternalServerError(t *testing.T) {
+	var failingPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return handler.AbortPipeGroup, errors.New("pipe failed")
+	}
+
+	h, err := handler.New(handler.PipeGroup{failingPipe}, struct{}{}, Converter)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.Handler().(http.HandlerFunc)(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "Internal Server Error\n", w.Body.String(), "error text is not sent to client")
+}
+
+func Test_Mounter_Router_ExpectRoutesServedByMux(t *testing.T) {
+	router := handler.NewRouter(Pipes, Converter)
+
+	err := router.Handle(
+		handler.Route{Method: http.MethodPost, Path: "/articles/:id", T: mockAction{}},
+		handler.Route{Method: http.MethodGet, Path: "/articles", T: mockFailingAction{}},
+	)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	assert.NoError(t, router.Mount(Mounter(mux)))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles/42", strings.NewReader(`{"title":"hello"}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/42", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// already mounted patterns conflict
+	assert.Error(t, router.Mount(Mounter(mux)))
+}
+
+type mockConflictAction struct{}
+
+func (a mockConflictAction) Action() (interface{}, error) {
+	return nil, handler.Conflict("article already exists")
+}
+
+func Test_Pipes_Problems_ExpectProblemJSON(t *testing.T) {
+	std := pipes.New(Adapter{}, pipes.Config{Problems: true})
+
+	h, err := handler.New(std.Group(), mockConflictAction{}, Converter)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.Handler().(http.HandlerFunc)(w, httptest.NewRequest(http.MethodPost, "/articles", nil))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, handler.ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"Conflict","status":409,"detail":"article already exists","instance":"/articles"}`, w.Body.String())
+}
+
+func Test_Problems_PipeFails_ExpectProblemJSON(t *testing.T) {
+	var failingPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return handler.AbortPipeGroup, handler.Validation(handler.FieldError{Field: "title", Message: "required"})
+	}
+
+	h, err := handler.New(handler.PipeGroup{failingPipe}, struct{}{}, Converter, Problems)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.Handler().(http.HandlerFunc)(w, httptest.NewRequest(http.MethodPost, "/articles?draft=1", nil))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, handler.ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "request is invalid",
+		"instance": "/articles?draft=1",
+		"errors": [{"field": "title", "message": "required"}]
+	}`, w.Body.String())
+}
+
+func Test_Problems_ResponseWritten_ExpectErrorNotWrittenAgain(t *testing.T) {
+	var failingPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		args[0].(http.ResponseWriter).WriteHeader(http.StatusAccepted)
+
+		return handler.AbortPipeGroup, errors.New("pipe failed")
+	}
+
+	h, err := handler.New(handler.PipeGroup{failingPipe}, struct{}{}, Converter, Problems)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.Handler().(http.HandlerFunc)(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func Test_ContentType_ExpectMediaTypeMatched(t *testing.T) {
+	predicate := ContentType("application/json", "application/x-www-form-urlencoded")
+
+	for contentType, expected := range map[string]bool{
+		"application/json; charset=utf-8":   true,
+		"Application/X-WWW-Form-Urlencoded": true,
+		"text/plain":                        false,
+		"":                                  false,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set("Content-Type", contentType)
+
+		assert.Equal(t, expected, predicate.Match(reflect.Value{}, httptest.NewRecorder(), r), contentType)
+	}
+
+	assert.Equal(t, "ContentType(application/json, application/x-www-form-urlencoded)", predicate.String())
+}
+
+func Test_ContentType_If_ExpectJSONAndFormBound(t *testing.T) {
+	var bound []mockRequest
+
+	var capture handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		bound = append(bound, v.FieldByName("Request").Interface().(mockRequest))
+
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	actionPipes := handler.PipeGroup{
+		handler.If(ContentType("application/x-www-form-urlencoded"),
+			handler.PipeGroup{BindFormPipe},
+			handler.PipeGroup{BindRequestPipe},
+		),
+		handler.PipeArray{capture, CallActionPipe, RenderPipe},
+	}
+
+	h, err := handler.New(actionPipes, mockAction{}, Converter)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /articles/{id}", h.Handler().(http.HandlerFunc))
+
+	for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded"} {
+		body := `{"title":"hello"}`
+		if contentType != "application/json" {
+			body = "title=hello"
+		}
+
+		r := httptest.NewRequest(http.MethodPost, "/articles/42?search=go", strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code, contentType)
+		assert.JSONEq(t, `{"title":"hello"}`, w.Body.String(), contentType)
+	}
+
+	assert.Equal(t, []mockRequest{{ID: 42, Search: "go", Title: "hello"}, {ID: 42, Search: "go", Title: "hello"}}, bound)
+}
@@ -0,0 +1,39 @@
+package nethttp
+
+import (
+	"mime"
+	"reflect"
+	"strings"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+// ContentType returns predicate what is true when media type of request body
+// is one of media types, parameters like charset are ignored
+//
+// Example:
+//
+//	handler.If(nethttp.ContentType("application/x-www-form-urlencoded"),
+//		handler.PipeGroup{nethttp.BindFormPipe},
+//		handler.PipeGroup{nethttp.BindRequestPipe},
+//	)
+func ContentType(mediaTypes ...string) handler.NamedCondition {
+	name := "ContentType(" + strings.Join(mediaTypes, ", ") + ")"
+
+	return handler.NamedPredicate(name, func(v reflect.Value, args ...interface{}) bool {
+		_, r := request(args)
+
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			return false
+		}
+
+		for _, t := range mediaTypes {
+			if strings.EqualFold(mediaType, t) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
@@ -1,7 +1,7 @@
 package handler
 
 // New creates new Handler
-func New(pipes PipeGroup, t interface{}, converter Converter) (*handler, error) {
+func New(pipes PipeGroup, t interface{}, converter Converter, options ...Option) (*handler, error) {
 	if pipes == nil {
 		return nil, ErrorPipesNil
 	}
@@ -14,11 +14,45 @@ func New(pipes PipeGroup, t interface{}, converter Converter) (*handler, error)
 		return nil, ErrorConverterNil
 	}
 
+	pipes, err := validatePipes(pipes)
+	if err != nil {
+		return nil, err
+	}
+
 	h := &handler{
 		pipesGroup: pipes,
 		t:          t,
 		convertTo:  converter,
+		context:    contextFromArgs,
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	h.plan = compile(pipes, h.hook != nil)
+
+	if h.problems != nil && h.problems.Writer == nil {
+		return nil, ErrorProblemWriterNil
+	}
+
+	if err := h.init(); err != nil {
+		return h, err
+	}
+
+	if h.typ != nil {
+		if h.metadata, err = MetadataOf(h.typ); err != nil {
+			return h, err
+		}
+	}
+
+	if h.hook != nil {
+		h.steps = steps(h.plan, h.typ)
+	}
+
+	if err := prepareParallel(h.plan, h.typ, h.hook != nil); err != nil {
+		return h, err
 	}
 
-	return h, h.init()
+	return h, h.initInjector()
 }
@@ -1,8 +1,10 @@
 package handler
 
 import (
-	"github.com/stretchr/testify/assert"
+	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_New_PipesNil_ExpectPipesNilError(t *testing.T) {
@@ -86,3 +88,28 @@ func Test_New_TCtorMap_ExpectError(t *testing.T) {
 
 	assert.Equal(t, ErrorInvalidConstructorType, err)
 }
+
+func Test_New_ReflectValueCtor_ExpectNotCalledAndTypeDeclared(t *testing.T) {
+	calls := 0
+
+	ctor := func() reflect.Value {
+		calls++
+
+		return reflect.ValueOf(mockStruct{})
+	}
+
+	h, err := New(mockPipes, ctor, converterMock)
+
+	assert.NoError(t, err)
+	assert.Nil(t, h.Type())
+
+	h, err = New(mockPipes, ctor, converterMock, WithType(reflect.TypeOf(&mockStruct{})))
+
+	assert.NoError(t, err)
+	assert.Equal(t, reflect.TypeOf(mockStruct{}), h.Type())
+	assert.Zero(t, calls, "constructor is called by requests only")
+
+	_, err = New(mockPipes, ctor, converterMock, WithType(reflect.TypeOf("")))
+
+	assert.Equal(t, ErrorTypeNotStruct, err)
+}
@@ -0,0 +1,549 @@
+// Package openapi generates OpenAPI 3 documents from handler types
+// declared as structs like
+//
+//	type GetArticle struct {
+//		Request struct {
+//			ID     int    `json:"-" param:"id"`
+//			Fields string `json:"-" query:"fields"`
+//		}
+//		Response Article
+//	}
+//
+// Path parameters are taken from fields of request tagged with `param:"name"`,
+// query parameters from fields tagged with `query:"name"`, other fields
+// are request body, or query parameters for GET, HEAD, DELETE and OPTIONS.
+// Response field describes response with status 200, handler without
+// response field responds with status 204.
+//
+// Schemas are derived from field types, `json` tags and `validate` tags
+// of both gopkg.in/validator.v2 and go-playground/validator syntax
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+var (
+	ErrorHandlerType = fmt.Errorf("openapi: handler type should be struct, pointer to struct or its constructor")
+	ErrorMethod      = fmt.Errorf("openapi: unsupported method")
+	ErrorDuplicate   = fmt.Errorf("openapi: duplicate operation")
+	ErrorPathParam   = fmt.Errorf("openapi: path has no parameter")
+)
+
+// Version is OpenAPI version of generated documents
+const Version = "3.0.3"
+
+// Handler describes handler registered with method and path.
+//
+// Empty method, path, summary, description and scopes are taken
+// from handler.Endpoint field of handler type
+type Handler struct {
+	// HTTP method, like "GET"
+	Method string
+
+	// Path with parameters in echo (/articles/:id)
+	// or net/http (/articles/{id}) syntax
+	Path string
+
+	// Value passed to handler.New: struct, pointer to struct,
+	// constructor function or reflect.Type of handler struct
+	T interface{}
+
+	// OperationID, name of handler type by default
+	ID string
+
+	Summary     string
+	Description string
+	Tags        []string
+
+	// Scopes required by Config.Security scheme
+	Scopes []string
+
+	// Response overrides response field, e.g. []Article{}
+	// when action returns slice of articles
+	Response interface{}
+
+	// Status of successful response, 200 or 204 by default
+	Status int
+
+	// Errors are kinds of errors handler returns in addition to Config.Errors,
+	// like handler.KindNotFound
+	Errors []handler.Kind
+}
+
+// Config configures generator.
+// Zero values are replaced with defaults
+type Config struct {
+	// Name of request field, "Request" by default
+	RequestField string
+
+	// Name of response field, "Response" by default
+	ResponseField string
+
+	// Errors are kinds of errors what every handler may return, each kind is
+	// documented as response with status of kind. KindInvalid, KindValidation
+	// and KindInternal by default
+	Errors []handler.Kind
+
+	// Error is a value what describes body of error responses, when it's nil
+	// errors are documented as problem details written by handler.WithProblems
+	Error interface{}
+
+	// Security is name of security scheme what scopes of handlers refer to
+	Security string
+
+	// SecuritySchemes are added to components of document
+	SecuritySchemes map[string]*SecurityScheme
+}
+
+// Generator collects handlers into document
+//
+// Example:
+//
+//	g := openapi.New(openapi.Info{Title: "Articles", Version: "1.0.0"}, openapi.Config{})
+//
+//	err := g.Add(
+//		openapi.Handler{Method: "GET", Path: "/articles", T: action.GetArticles{}, Response: []action.Article{}},
+//		openapi.Handler{Method: "POST", Path: "/articles", T: action.CreateArticle{}},
+//	)
+//
+//	yaml, err := g.Document().YAML()
+type Generator struct {
+	config  Config
+	doc     *Document
+	schemas *schemas
+}
+
+// New creates generator of document with info
+func New(info Info, config Config) *Generator {
+	if config.RequestField == "" {
+		config.RequestField = "Request"
+	}
+
+	if config.ResponseField == "" {
+		config.ResponseField = "Response"
+	}
+
+	if config.Errors == nil {
+		config.Errors = []handler.Kind{handler.KindInvalid, handler.KindValidation, handler.KindInternal}
+	}
+
+	s := newSchemas()
+
+	return &Generator{
+		config: config,
+		doc: &Document{
+			OpenAPI:    Version,
+			Info:       info,
+			Paths:      map[string]PathItem{},
+			Components: &Components{Schemas: s.components, SecuritySchemes: config.SecuritySchemes},
+		},
+		schemas: s,
+	}
+}
+
+// Generate generates document of handlers
+func Generate(info Info, config Config, handlers ...Handler) (*Document, error) {
+	g := New(info, config)
+
+	if err := g.Add(handlers...); err != nil {
+		return nil, err
+	}
+
+	return g.Document(), nil
+}
+
+// Add adds operations of handlers to document
+func (g *Generator) Add(handlers ...Handler) error {
+	for _, h := range handlers {
+		if err := g.add(h); err != nil {
+			return fmt.Errorf("%w: %s %s", err, h.Method, h.Path)
+		}
+	}
+
+	return nil
+}
+
+// Document returns generated document
+func (g *Generator) Document() *Document {
+	return g.doc
+}
+
+func (g *Generator) add(h Handler) error {
+	t, err := TypeOf(h.T)
+	if err != nil {
+		return err
+	}
+
+	h, err = withMetadata(h, t)
+	if err != nil {
+		return err
+	}
+
+	method := strings.ToLower(h.Method)
+
+	if !supportedMethods[method] {
+		return ErrorMethod
+	}
+
+	path, pathParams := convertPath(h.Path)
+
+	if _, exists := g.doc.Paths[path][method]; exists {
+		return ErrorDuplicate
+	}
+
+	op := &Operation{
+		OperationID: h.ID,
+		Summary:     h.Summary,
+		Description: h.Description,
+		Tags:        h.Tags,
+		Responses:   map[string]*Response{},
+	}
+
+	if op.OperationID == "" {
+		op.OperationID = t.Name()
+	}
+
+	if g.config.Security != "" && len(h.Scopes) > 0 {
+		op.Security = []SecurityRequirement{{g.config.Security: h.Scopes}}
+	}
+
+	if err := g.request(op, t, method, pathParams); err != nil {
+		return err
+	}
+
+	g.responses(op, t, h)
+
+	if g.doc.Paths[path] == nil {
+		g.doc.Paths[path] = PathItem{}
+	}
+
+	g.doc.Paths[path][method] = op
+
+	return nil
+}
+
+var supportedMethods = map[string]bool{
+	"get":     true,
+	"put":     true,
+	"post":    true,
+	"delete":  true,
+	"options": true,
+	"head":    true,
+	"patch":   true,
+	"trace":   true,
+}
+
+// methods without request body
+var queryMethods = map[string]bool{
+	"get":     true,
+	"head":    true,
+	"delete":  true,
+	"options": true,
+}
+
+// request adds parameters and request body of handler type t to operation
+func (g *Generator) request(op *Operation, t reflect.Type, method string, pathParams []string) error {
+	declared := map[string]bool{}
+
+	if field, ok := t.FieldByName(g.config.RequestField); ok {
+		request := indirect(field.Type)
+
+		if request.Kind() == reflect.Struct {
+			body, bodyOnly, err := g.parameters(op, request, method, pathParams, declared)
+			if err != nil {
+				return err
+			}
+
+			if len(body.Properties) > 0 {
+				if bodyOnly && request.Name() != "" {
+					body = g.schemas.schema(request)
+				}
+
+				op.RequestBody = &RequestBody{
+					Required: true,
+					Content:  jsonContent(body),
+				}
+			}
+		}
+	}
+
+	// path parameters what are not bound to request
+	for _, name := range pathParams {
+		if !declared[name] {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	return nil
+}
+
+// parameters adds parameters from fields of request struct to operation
+// and returns schema of fields left for body, bodyOnly is true if all fields are body
+func (g *Generator) parameters(op *Operation, request reflect.Type, method string, pathParams []string, declared map[string]bool) (body *Schema, bodyOnly bool, err error) {
+	body = &Schema{Type: "object", Properties: map[string]*Schema{}}
+	bodyOnly = !queryMethods[method]
+
+	err = walkFields(request, func(field reflect.StructField) error {
+		if name, ok := field.Tag.Lookup("param"); ok {
+			if !contains(pathParams, name) {
+				return fmt.Errorf("%w %q", ErrorPathParam, name)
+			}
+
+			declared[name] = true
+			bodyOnly = false
+
+			op.Parameters = append(op.Parameters, g.parameter(field, name, "path"))
+
+			return nil
+		}
+
+		if name, ok := field.Tag.Lookup("query"); ok {
+			bodyOnly = false
+
+			op.Parameters = append(op.Parameters, g.parameter(field, name, "query"))
+
+			return nil
+		}
+
+		name, skip := jsonName(field)
+		if skip {
+			return nil
+		}
+
+		if queryMethods[method] {
+			op.Parameters = append(op.Parameters, g.parameter(field, name, "query"))
+
+			return nil
+		}
+
+		g.schemas.property(body, field, name)
+
+		return nil
+	})
+
+	return body, bodyOnly, err
+}
+
+func (g *Generator) parameter(field reflect.StructField, name, in string) *Parameter {
+	schema, required := g.schemas.field(field)
+
+	return &Parameter{
+		Name:     name,
+		In:       in,
+		Required: required || in == "path",
+		Schema:   schema,
+	}
+}
+
+// responses adds successful and error responses of handler type t to operation
+func (g *Generator) responses(op *Operation, t reflect.Type, h Handler) {
+	var body *Schema
+
+	if h.Response != nil {
+		body = g.schemas.schema(reflect.TypeOf(h.Response))
+	} else if field, ok := t.FieldByName(g.config.ResponseField); ok {
+		body = g.schemas.schema(field.Type)
+	}
+
+	status := h.Status
+
+	if status == 0 {
+		status = http.StatusOK
+
+		if body == nil {
+			status = http.StatusNoContent
+		}
+	}
+
+	response := &Response{Description: http.StatusText(status)}
+
+	if body != nil && status != http.StatusNoContent {
+		response.Content = jsonContent(body)
+	}
+
+	op.Responses[fmt.Sprint(status)] = response
+
+	for _, kind := range append(append([]handler.Kind(nil), g.config.Errors...), h.Errors...) {
+		g.errorResponse(op, kind)
+	}
+}
+
+// errorResponse adds response of error kind to operation
+func (g *Generator) errorResponse(op *Operation, kind handler.Kind) {
+	status := kind.Status()
+
+	response := &Response{Description: handler.NewProblem(status, nil).Title}
+
+	switch {
+	case g.config.Error != nil:
+		response.Content = jsonContent(g.schemas.schema(reflect.TypeOf(g.config.Error)))
+	case kind == handler.KindValidation:
+		response.Content = problemContent(g.schemas.validationProblem())
+	default:
+		response.Content = problemContent(g.schemas.problem())
+	}
+
+	op.Responses[fmt.Sprint(status)] = response
+}
+
+// withMetadata fills empty fields of h from handler.Endpoint field of handler type t
+func withMetadata(h Handler, t reflect.Type) (Handler, error) {
+	metadata, err := handler.MetadataOf(t)
+	if err != nil {
+		return h, err
+	}
+
+	if h.Method == "" {
+		h.Method = metadata.Method
+	}
+
+	if h.Path == "" {
+		h.Path = metadata.Path
+	}
+
+	if h.Summary == "" {
+		h.Summary = metadata.Summary
+	}
+
+	if h.Description == "" {
+		h.Description = metadata.Description
+	}
+
+	if h.Scopes == nil {
+		h.Scopes = metadata.Scopes
+	}
+
+	return h, nil
+}
+
+// Handlers returns handlers of routes registered in handler.Router
+//
+// Example:
+//
+//	doc, err := openapi.Generate(info, openapi.Config{}, openapi.Handlers(router.Routes())...)
+func Handlers(routes []handler.RouteInfo) []Handler {
+	handlers := make([]Handler, 0, len(routes))
+
+	for _, route := range routes {
+		handlers = append(handlers, Handler{
+			Method: route.Method,
+			Path:   route.Path,
+			T:      route.Type,
+		})
+	}
+
+	return handlers
+}
+
+// TypeOf returns struct type of value passed to handler.New
+func TypeOf(t interface{}) (reflect.Type, error) {
+	if typ, ok := t.(reflect.Type); ok {
+		return structType(typ)
+	}
+
+	if ctor, ok := t.(func() reflect.Value); ok {
+		v := ctor()
+		if !v.IsValid() {
+			return nil, ErrorHandlerType
+		}
+
+		return structType(v.Type())
+	}
+
+	if t == nil {
+		return nil, ErrorHandlerType
+	}
+
+	typ := reflect.TypeOf(t)
+
+	if typ.Kind() == reflect.Func {
+		if typ.NumOut() == 0 {
+			return nil, ErrorHandlerType
+		}
+
+		typ = typ.Out(0)
+	}
+
+	return structType(typ)
+}
+
+func structType(t reflect.Type) (reflect.Type, error) {
+	t = indirect(t)
+
+	if t.Kind() != reflect.Struct {
+		return nil, ErrorHandlerType
+	}
+
+	return t, nil
+}
+
+// convertPath converts echo and net/http path parameters to OpenAPI syntax
+// and returns names of parameters
+func convertPath(path string) (string, []string) {
+	var params []string
+
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		var name string
+
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name = segment[1:]
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			name = strings.TrimSuffix(segment[1:len(segment)-1], "...")
+		default:
+			continue
+		}
+
+		params = append(params, name)
+		segments[i] = "{" + name + "}"
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+func problemContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{handler.ProblemContentType: {Schema: schema}}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// JSON returns indented JSON representation of document
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// YAML returns YAML representation of document
+func (d *Document) YAML() ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonToYAML(data)
+}
@@ -0,0 +1,280 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	mockArticle struct {
+		ID        int       `json:"id"`
+		Title     string    `json:"title"`
+		Tags      []string  `json:"tags,omitempty"`
+		Author    *mockUser `json:"author"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	mockUser struct {
+		Name string `json:"name"`
+
+		// recursive type
+		Friends []mockUser `json:"friends"`
+	}
+
+	mockGetArticles struct {
+		Request struct {
+			Page   int    `json:"page" validate:"min=1"`
+			Search string `json:"-" query:"q"`
+		}
+	}
+
+	mockGetArticle struct {
+		Request struct {
+			ID int `json:"-" param:"id"`
+		}
+		Response mockArticle
+	}
+
+	mockCreateArticleRequest struct {
+		Title string `json:"title" validate:"min=3,max=40,regexp=^[a-zA-Z]*$"`
+		Body  string `json:"body" validate:"nonzero"`
+		Kind  string `json:"kind" validate:"required,oneof=news blog"`
+	}
+
+	mockCreateArticle struct {
+		Request mockCreateArticleRequest
+	}
+)
+
+var (
+	mockInfo = Info{Title: "Articles", Version: "1.0.0"}
+
+	mockPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return &v, nil
+	}
+
+	mockConverter handler.Converter = func(f handler.GenericHandlerFunc) interface{} {
+		return f
+	}
+)
+
+func generateMock(t *testing.T) *Document {
+	doc, err := Generate(mockInfo, Config{},
+		Handler{Method: "GET", Path: "/articles", T: mockGetArticles{}, Response: []mockArticle{}},
+		Handler{Method: "GET", Path: "/articles/:id", T: &mockGetArticle{}, Summary: "Get article", Errors: []handler.Kind{handler.KindNotFound}},
+		Handler{Method: "POST", Path: "/articles", T: func() *mockCreateArticle { return nil }},
+		Handler{Method: "DELETE", Path: "/articles/{id}/tags/{tag...}", T: reflect.TypeOf(mockCreateArticle{})},
+	)
+	assert.NoError(t, err)
+
+	return doc
+}
+
+func Test_Generate_ExpectParametersFromTags(t *testing.T) {
+	doc := generateMock(t)
+
+	list := doc.Paths["/articles"]["get"]
+
+	assert.Equal(t, "mockGetArticles", list.OperationID)
+	assert.Nil(t, list.RequestBody)
+	assert.Equal(t, []*Parameter{
+		{Name: "page", In: "query", Schema: &Schema{Type: "integer", Format: "int64", Minimum: float(1)}},
+		{Name: "q", In: "query", Schema: &Schema{Type: "string"}},
+	}, list.Parameters)
+
+	get := doc.Paths["/articles/{id}"]["get"]
+
+	assert.Equal(t, "Get article", get.Summary)
+	assert.Equal(t, []*Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer", Format: "int64"}},
+	}, get.Parameters)
+
+	remove := doc.Paths["/articles/{id}/tags/{tag}"]["delete"]
+
+	assert.Equal(t, []string{"id", "tag"}, []string{remove.Parameters[3].Name, remove.Parameters[4].Name})
+}
+
+func Test_Generate_ExpectBodyAndResponseSchemas(t *testing.T) {
+	doc := generateMock(t)
+
+	create := doc.Paths["/articles"]["post"]
+
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/mockCreateArticleRequest"}, create.RequestBody.Content["application/json"].Schema)
+	assert.Equal(t, "No Content", create.Responses["204"].Description)
+	assert.Contains(t, create.Responses, "400")
+
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"title": {Type: "string", MinLength: integer(3), MaxLength: integer(40), Pattern: "^[a-zA-Z]*$"},
+			"body":  {Type: "string"},
+			"kind":  {Type: "string", Enum: []interface{}{"news", "blog"}},
+		},
+		Required: []string{"body", "kind"},
+	}, doc.Components.Schemas["mockCreateArticleRequest"])
+
+	list := doc.Paths["/articles"]["get"].Responses["200"].Content["application/json"].Schema
+
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/mockArticle"}}, list)
+
+	article := doc.Components.Schemas["mockArticle"]
+
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, article.Properties["created_at"])
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/mockUser"}, article.Properties["author"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/mockUser"}}, doc.Components.Schemas["mockUser"].Properties["friends"])
+}
+
+func Test_Generate_ExpectProblemResponsesPerKind(t *testing.T) {
+	doc := generateMock(t)
+
+	get := doc.Paths["/articles/{id}"]["get"]
+
+	assert.ElementsMatch(t, []string{"200", "400", "404", "422", "500"}, keys(get.Responses))
+	assert.Equal(t, "Not Found", get.Responses["404"].Description)
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/Problem"}, get.Responses["404"].Content[handler.ProblemContentType].Schema)
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/ValidationProblem"}, get.Responses["422"].Content[handler.ProblemContentType].Schema)
+	assert.NotContains(t, doc.Paths["/articles"]["get"].Responses, "404")
+
+	validation := doc.Components.Schemas["ValidationProblem"]
+
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/FieldError"}}, validation.Properties["errors"])
+	assert.Contains(t, validation.Properties, "title")
+	assert.Equal(t, []string{"title", "status"}, doc.Components.Schemas["Problem"].Required)
+	assert.NotContains(t, doc.Components.Schemas["Problem"].Properties, "errors")
+}
+
+func Test_Generate_CustomError_ExpectJSONErrorResponses(t *testing.T) {
+	type mockError struct {
+		Error string `json:"error"`
+	}
+
+	doc, err := Generate(mockInfo, Config{Error: mockError{}, Errors: []handler.Kind{handler.KindInvalid}},
+		Handler{Method: "GET", Path: "/articles", T: mockGetArticles{}, Errors: []handler.Kind{handler.KindUnauthorized}},
+	)
+	assert.NoError(t, err)
+
+	responses := doc.Paths["/articles"]["get"].Responses
+
+	assert.ElementsMatch(t, []string{"204", "400", "401"}, keys(responses))
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/mockError"}, responses["401"].Content["application/json"].Schema)
+	assert.NotContains(t, doc.Components.Schemas, "Problem")
+}
+
+func keys(responses map[string]*Response) []string {
+	var keys []string
+
+	for key := range responses {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func Test_Generate_InvalidHandlers_ExpectErrors(t *testing.T) {
+	cases := []struct {
+		handler Handler
+		err     error
+	}{
+		{Handler{Method: "GET", Path: "/", T: 1}, ErrorHandlerType},
+		{Handler{Method: "FETCH", Path: "/", T: mockGetArticles{}}, ErrorMethod},
+		{Handler{Method: "GET", Path: "/article", T: mockGetArticle{}}, ErrorPathParam},
+		{Handler{Method: "GET", Path: "/articles", T: mockGetArticles{}}, ErrorDuplicate},
+	}
+
+	for _, tc := range cases {
+		_, err := Generate(mockInfo, Config{}, Handler{Method: "GET", Path: "/articles", T: mockGetArticles{}}, tc.handler)
+
+		assert.True(t, errors.Is(err, tc.err), err)
+	}
+}
+
+func Test_Document_YAML_ExpectSameAsJSON(t *testing.T) {
+	doc := generateMock(t)
+
+	data, err := doc.JSON()
+	assert.NoError(t, err)
+
+	yamlData, err := doc.YAML()
+	assert.NoError(t, err)
+
+	var decoded interface{}
+	assert.NoError(t, yaml.Unmarshal(yamlData, &decoded))
+
+	fromYAML, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, string(data), string(fromYAML))
+}
+
+func Test_JSONToYAML_ExpectOrderPreservedAndAmbiguousStringsQuoted(t *testing.T) {
+	data, err := jsonToYAML([]byte(`{"openapi":"3.0.3","list":[{"a":"yes","b":[]},["x"],"1"],"empty":{},"count":1.5,"n":null}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `openapi: 3.0.3
+list:
+  - a: "yes"
+    b: []
+  - - x
+  - "1"
+empty: {}
+count: 1.5
+"n": null
+`, string(data))
+}
+
+func Test_Handlers_Router_ExpectOperationsOfRoutes(t *testing.T) {
+	router := handler.NewRouter(handler.PipeGroup{[]handler.Pipe{mockPipe}}, mockConverter)
+
+	err := router.Handle(
+		handler.Route{Method: "GET", Path: "/articles/:id", T: mockGetArticle{}},
+		handler.Route{Method: "POST", Path: "/articles", T: &mockCreateArticle{}},
+	)
+	assert.NoError(t, err)
+
+	doc, err := Generate(mockInfo, Config{}, Handlers(router.Routes())...)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mockGetArticle", doc.Paths["/articles/{id}"]["get"].OperationID)
+	assert.Equal(t, "mockCreateArticle", doc.Paths["/articles"]["post"].OperationID)
+}
+
+type mockDeleteArticle struct {
+	handler.Endpoint `method:"DELETE" path:"/articles/:id" summary:"Delete article" scope:"articles:write"`
+
+	Request struct {
+		ID int `json:"-" param:"id"`
+	}
+}
+
+func Test_Generate_Endpoint_ExpectMetadataAndSecurity(t *testing.T) {
+	doc, err := Generate(mockInfo, Config{
+		Security: "oauth",
+		SecuritySchemes: map[string]*SecurityScheme{
+			"oauth": {Type: "oauth2", Flows: &OAuthFlows{ClientCredentials: &OAuthFlow{
+				TokenURL: "/token",
+				Scopes:   map[string]string{"articles:write": "Write articles"},
+			}}},
+		},
+	}, Handler{T: mockDeleteArticle{}})
+
+	assert.NoError(t, err)
+
+	op := doc.Paths["/articles/{id}"]["delete"]
+
+	assert.Equal(t, "Delete article", op.Summary)
+	assert.Equal(t, []SecurityRequirement{{"oauth": {"articles:write"}}}, op.Security)
+	assert.Contains(t, doc.Components.SecuritySchemes, "oauth")
+}
+
+func Test_TypeOf_CtorReturnsInvalidValue_ExpectError(t *testing.T) {
+	_, err := TypeOf(func() reflect.Value { return reflect.Value{} })
+
+	assert.ErrorIs(t, err, ErrorHandlerType)
+}
@@ -0,0 +1,424 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+const componentsRef = "#/components/schemas/"
+
+// schemas builds schemas of types, named structs are placed in components
+type schemas struct {
+	components map[string]*Schema
+
+	// names of components by type and types by component names
+	names map[reflect.Type]string
+	types map[string]reflect.Type
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: map[string]*Schema{},
+		names:      map[reflect.Type]string{},
+		types:      map[string]reflect.Type{},
+	}
+}
+
+// schema returns schema of type t
+func (s *schemas) schema(t reflect.Type) *Schema {
+	t = indirect(t)
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// custom JSON representation is unknown
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: float(0)}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: s.schema(t.Elem()), MinItems: integer(t.Len()), MaxItems: integer(t.Len())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+
+		return &Schema{Ref: componentsRef + s.component(t)}
+	}
+
+	// interfaces and types what can't be encoded
+	return &Schema{}
+}
+
+// component returns name of component of named struct,
+// component is built on first use
+func (s *schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := componentName(t.Name())
+
+	// same name in different packages
+	if _, taken := s.types[name]; taken {
+		pkg := t.PkgPath()
+		name = componentName(pkg[strings.LastIndex(pkg, "/")+1:] + "." + t.Name())
+
+		for i := 2; s.types[name] != nil; i++ {
+			name = componentName(fmt.Sprintf("%s.%s%d", pkg, t.Name(), i))
+		}
+	}
+
+	// registered before building, so recursive types refer to it
+	s.names[t] = name
+	s.types[name] = t
+
+	s.components[name] = s.object(t)
+
+	return name
+}
+
+var invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// componentName replaces characters what are not allowed in component names,
+// like brackets of generic types
+func componentName(name string) string {
+	return strings.Trim(invalidComponentChars.ReplaceAllString(name, "_"), "_")
+}
+
+// object returns inline schema of struct type t
+func (s *schemas) object(t reflect.Type) *Schema {
+	object := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	_ = walkFields(t, func(field reflect.StructField) error {
+		if name, skip := jsonName(field); !skip {
+			s.property(object, field, name)
+		}
+
+		return nil
+	})
+
+	return object
+}
+
+// property adds field to properties of object
+func (s *schemas) property(object *Schema, field reflect.StructField, name string) {
+	schema, required := s.field(field)
+
+	object.Properties[name] = schema
+
+	if required {
+		object.Required = append(object.Required, name)
+	}
+}
+
+// field returns schema of struct field and whether field is required by validate tag
+func (s *schemas) field(field reflect.StructField) (*Schema, bool) {
+	var schema *Schema
+
+	if jsonOption(field, "string") {
+		schema = &Schema{Type: "string"}
+	} else {
+		schema = s.schema(field.Type)
+	}
+
+	if field.Type.Kind() == reflect.Ptr && schema.Ref == "" {
+		schema.Nullable = true
+	}
+
+	return schema, applyValidate(schema, indirect(field.Type), field.Tag.Get("validate"))
+}
+
+// walkFields calls fn for exported fields of struct type t
+// and fields of embedded structs what are encoded as part of t
+func walkFields(t reflect.Type, fn func(field reflect.StructField) error) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+
+		// embedded struct without name in json tag is encoded inline
+		if field.Anonymous && indirect(field.Type).Kind() == reflect.Struct && tag != "-" && strings.Split(tag, ",")[0] == "" {
+			if err := walkFields(indirect(field.Type), fn); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonName returns name of field in JSON
+func jsonName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+
+	if tag == "-" {
+		return "", true
+	}
+
+	name, _, _ = strings.Cut(tag, ",")
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, false
+}
+
+// jsonOption reports whether json tag of field has option
+func jsonOption(field reflect.StructField, option string) bool {
+	_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+	return hasOption(options, option)
+}
+
+func hasOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyValidate applies rules of validate tag to schema of type t
+// and reports whether field is required
+func applyValidate(schema *Schema, t reflect.Type, tag string) (required bool) {
+	if tag == "" || tag == "-" {
+		return false
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required", "nonzero":
+			required = true
+		case "min", "gte":
+			setMin(schema, t, param, false)
+		case "max", "lte":
+			setMax(schema, t, param, false)
+		case "gt":
+			setMin(schema, t, param, true)
+		case "lt":
+			setMax(schema, t, param, true)
+		case "len":
+			setMin(schema, t, param, false)
+			setMax(schema, t, param, false)
+		case "regexp":
+			schema.Pattern = param
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, enumValue(t, value))
+			}
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "ipv4", "ipv6", "hostname":
+			schema.Format = name
+		}
+	}
+
+	return required
+}
+
+func setMin(schema *Schema, t reflect.Type, param string, exclusive bool) {
+	switch {
+	case isNumber(t):
+		if value, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.Minimum = &value
+			schema.ExclusiveMinimum = exclusive
+		}
+	case t.Kind() == reflect.String:
+		if value, err := strconv.Atoi(param); err == nil {
+			if exclusive {
+				value++
+			}
+
+			schema.MinLength = &value
+		}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if value, err := strconv.Atoi(param); err == nil {
+			if exclusive {
+				value++
+			}
+
+			schema.MinItems = &value
+		}
+	}
+}
+
+func setMax(schema *Schema, t reflect.Type, param string, exclusive bool) {
+	switch {
+	case isNumber(t):
+		if value, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.Maximum = &value
+			schema.ExclusiveMaximum = exclusive
+		}
+	case t.Kind() == reflect.String:
+		if value, err := strconv.Atoi(param); err == nil {
+			if exclusive {
+				value--
+			}
+
+			schema.MaxLength = &value
+		}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if value, err := strconv.Atoi(param); err == nil {
+			if exclusive {
+				value--
+			}
+
+			schema.MaxItems = &value
+		}
+	}
+}
+
+func enumValue(t reflect.Type, value string) interface{} {
+	if isNumber(t) {
+		return json.Number(value)
+	}
+
+	return value
+}
+
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func integer(value int) *int {
+	return &value
+}
+
+var (
+	problemType    = reflect.TypeOf(handler.Problem{})
+	fieldErrorType = reflect.TypeOf(handler.FieldError{})
+)
+
+// problem returns schema of handler.Problem, it's built by hand
+// because problem has custom JSON representation
+func (s *schemas) problem() *Schema {
+	return s.handBuilt("Problem", problemType, func() *Schema {
+		return &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"type":     {Type: "string"},
+				"title":    {Type: "string"},
+				"status":   {Type: "integer", Format: "int32"},
+				"detail":   {Type: "string"},
+				"instance": {Type: "string"},
+			},
+			Required: []string{"title", "status"},
+		}
+	})
+}
+
+// validationProblem returns schema of handler.Problem of KindValidation error
+// with invalid fields in "errors" member
+func (s *schemas) validationProblem() *Schema {
+	// there is no separate type, pointer type only reserves component name
+	return s.handBuilt("ValidationProblem", reflect.PointerTo(problemType), func() *Schema {
+		s.problem()
+
+		problem := *s.components[s.names[problemType]]
+		problem.Properties = map[string]*Schema{"errors": {Type: "array", Items: s.schema(fieldErrorType)}}
+
+		for name, property := range s.components[s.names[problemType]].Properties {
+			problem.Properties[name] = property
+		}
+
+		return &problem
+	})
+}
+
+// handBuilt returns reference to component of type t what is built by build
+// on first use, name is prefixed with package like in component if it's taken
+func (s *schemas) handBuilt(name string, t reflect.Type, build func() *Schema) *Schema {
+	if name, ok := s.names[t]; ok {
+		return &Schema{Ref: componentsRef + name}
+	}
+
+	if _, taken := s.types[name]; taken {
+		name = "handler." + name
+	}
+
+	s.names[t] = name
+	s.types[name] = t
+	s.components[name] = build()
+
+	return &Schema{Ref: componentsRef + name}
+}
@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jsonToYAML converts JSON to block style YAML,
+// order of keys is the same as in JSON
+func jsonToYAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	node, err := decodeNode(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeNode decodes next JSON value into YAML node,
+// so keys of objects keep their order
+func decodeNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+		if token == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+
+		for decoder.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				node.Content = append(node.Content, stringNode(key.(string)))
+			}
+
+			value, err := decodeNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, value)
+		}
+
+		// closing delimiter
+		_, err = decoder.Token()
+
+		return node, err
+	case string:
+		return stringNode(token), nil
+	case json.Number:
+		if strings.ContainsAny(token.String(), ".eE") {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: token.String()}, nil
+		}
+
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: token.String()}, nil
+	case bool:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+
+		if token {
+			node.Value = "true"
+		}
+
+		return node, nil
+	}
+
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+}
+
+// words what YAML 1.1 parsers read as booleans
+var booleanYAMLWords = map[string]bool{
+	"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true,
+}
+
+// stringNode returns node of string, encoder quotes strings what look like
+// other types, and words of YAML 1.1 booleans are quoted explicitly
+func stringNode(s string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+
+	if booleanYAMLWords[strings.ToLower(s)] {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+
+	return node
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+)
+
+// Option configures handler
+type Option func(*handler)
+
+// WithContext sets func what extracts context.Context from arguments
+// passed to generic handler by converter.
+//
+// By default context is taken from first argument what is context.Context,
+// has Context() method like *http.Request or has Request() method
+// like echo.Context
+//
+// Example:
+//
+//	handler.New(pipes, MyHandler{}, converter, handler.WithContext(func(args ...interface{}) context.Context {
+//		return args[0].(*fasthttp.RequestCtx)
+//	}))
+func WithContext(f func(args ...interface{}) context.Context) Option {
+	return func(h *handler) {
+		h.context = f
+	}
+}
+
+// WithType declares struct type of instances created by func() reflect.Value constructor.
+// New doesn't call such constructor, so without this option type is unknown,
+// route metadata and services injection are not available and fields
+// owned by branches of parallel groups are checked only when request runs.
+// Pointer to struct type is accepted too
+//
+// Example:
+//
+//	handler.New(pipes, newMyHandler, converter, handler.WithType(reflect.TypeOf(MyHandler{})))
+func WithType(t reflect.Type) Option {
+	return func(h *handler) {
+		h.typ = t
+	}
+}
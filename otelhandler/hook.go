@@ -0,0 +1,123 @@
+// Package otelhandler provides handler.Hook what creates OpenTelemetry spans
+// for groups and pipes of handlers
+package otelhandler
+
+import (
+	"context"
+
+	"github.com/mykytanikitenko/go-handle"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is name of tracer what creates spans
+const TracerName = "github.com/mykytanikitenko/go-handle/otelhandler"
+
+// Attributes of spans
+const (
+	HandlerKey = attribute.Key("handler.type")
+	PipeKey    = attribute.Key("handler.pipe")
+	KindKey    = attribute.Key("handler.step")
+	PathKey    = attribute.Key("handler.path")
+	TimeoutKey = attribute.Key("handler.timeout")
+	AbortedKey = attribute.Key("handler.aborted")
+
+	DescriptionKey = attribute.Key("handler.pipe.description")
+	TagsKey        = attribute.Key("handler.pipe.tags")
+)
+
+// Hook creates span for each group and pipe.
+// Span of root group is named by handler type, spans of nested groups
+// by kind and path like "array[1]", spans of pipes by pipe name
+//
+// Example:
+//
+//	handler.New(pipes, action.GetArticles{}, nethttp.Converter, handler.WithHooks(otelhandler.New(nil)))
+type Hook struct {
+	tracer trace.Tracer
+}
+
+var _ handler.Hook = (*Hook)(nil)
+
+// New creates hook what uses tracer of provider,
+// global provider is used if provider is nil
+func New(provider trace.TracerProvider) *Hook {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return &Hook{tracer: provider.Tracer(TracerName)}
+}
+
+func (h *Hook) EnterGroup(ctx context.Context, group handler.Step) context.Context {
+	name := group.Name + group.Path.String()
+
+	if len(group.Path) == 0 && group.Handler != nil {
+		name = group.Handler.String()
+	}
+
+	ctx, _ = h.tracer.Start(ctx, name, trace.WithAttributes(attributes(group)...))
+
+	return ctx
+}
+
+func (h *Hook) ExitGroup(ctx context.Context, group handler.Step, result handler.Result) {
+	end(ctx, result)
+}
+
+func (h *Hook) BeforePipe(ctx context.Context, pipe handler.Step) context.Context {
+	ctx, _ = h.tracer.Start(ctx, pipe.Name, trace.WithAttributes(attributes(pipe)...))
+
+	return ctx
+}
+
+func (h *Hook) AfterPipe(ctx context.Context, pipe handler.Step, result handler.Result) {
+	end(ctx, result)
+}
+
+func attributes(step handler.Step) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		KindKey.String(step.Kind.String()),
+		PathKey.String(step.Path.String()),
+	}
+
+	if step.Handler != nil {
+		attributes = append(attributes, HandlerKey.String(step.Handler.String()))
+	}
+
+	if step.Kind == handler.StepPipe {
+		attributes = append(attributes, PipeKey.String(step.Name))
+	}
+
+	if step.Description != "" {
+		attributes = append(attributes, DescriptionKey.String(step.Description))
+	}
+
+	if len(step.Tags) > 0 {
+		attributes = append(attributes, TagsKey.StringSlice(step.Tags))
+	}
+
+	if step.Timeout > 0 {
+		attributes = append(attributes, TimeoutKey.String(step.Timeout.String()))
+	}
+
+	return attributes
+}
+
+// end ends span of step
+func end(ctx context.Context, result handler.Result) {
+	span := trace.SpanFromContext(ctx)
+
+	if result.Aborted {
+		span.SetAttributes(AbortedKey.Bool(true))
+	}
+
+	if result.Err != nil {
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,98 @@
+package otelhandler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type mockHandler struct{}
+
+var nopPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	return handler.ContinuePipeGroup(v), nil
+}
+
+func run(t *testing.T, pipes handler.PipeGroup) (*tracetest.InMemoryExporter, error) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	h, err := handler.New(pipes, mockHandler{}, func(f handler.GenericHandlerFunc) interface{} {
+		return f
+	}, handler.WithHooks(New(provider)))
+	assert.NoError(t, err)
+
+	return exporter, h.Handler().(handler.GenericHandlerFunc)(context.Background())
+}
+
+func Test_Hook_ExpectSpanPerGroupAndPipe(t *testing.T) {
+	var parent trace.SpanContext
+
+	var pipe handler.ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		parent = trace.SpanFromContext(ctx).SpanContext()
+
+		return handler.AbortPipeGroup, nil
+	}
+
+	exporter, err := run(t, handler.PipeGroup{[]handler.Pipe{nopPipe}, handler.PipeGroup{pipe}})
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+
+	// spans are exported when ended
+	assert.Len(t, names, 5)
+	assert.Equal(t, "array[0]", names[1])
+	assert.Equal(t, "group[1]", names[3])
+	assert.Equal(t, "otelhandler.mockHandler", names[4])
+
+	pipeSpan := spans[2]
+
+	assert.Equal(t, parent, pipeSpan.SpanContext, "context pipe receives its span")
+	assert.Equal(t, spans[3].SpanContext.SpanID(), pipeSpan.Parent.SpanID())
+	assert.Contains(t, pipeSpan.Attributes, HandlerKey.String("otelhandler.mockHandler"))
+	assert.Contains(t, pipeSpan.Attributes, PathKey.String("[1][0]"))
+	assert.Contains(t, pipeSpan.Attributes, AbortedKey.Bool(true))
+}
+
+func Test_Hook_PipeFails_ExpectErrorStatus(t *testing.T) {
+	mockError := errors.New("pipe failed")
+
+	var failingPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return handler.AbortPipeGroup, mockError
+	}
+
+	exporter, err := run(t, handler.PipeGroup{failingPipe})
+	assert.Equal(t, mockError, err)
+
+	for _, span := range exporter.GetSpans() {
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.Equal(t, "pipe failed", span.Status.Description)
+		assert.Len(t, span.Events, 1)
+	}
+}
+
+func Test_Hook_NamedPipe_ExpectSpanNamedWithDescriptionAndTags(t *testing.T) {
+	bind := handler.Named("BindRequest", nopPipe, handler.Description("binds request"), handler.Tags("http", "io"))
+
+	exporter, err := run(t, handler.PipeGroup{bind})
+	assert.NoError(t, err)
+
+	pipeSpan := exporter.GetSpans()[0]
+
+	assert.Equal(t, "BindRequest", pipeSpan.Name)
+	assert.Contains(t, pipeSpan.Attributes, PipeKey.String("BindRequest"))
+	assert.Contains(t, pipeSpan.Attributes, DescriptionKey.String("binds request"))
+	assert.Contains(t, pipeSpan.Attributes, TagsKey.StringSlice([]string{"http", "io"}))
+}
@@ -0,0 +1,31 @@
+package otelhandler
+
+import (
+	"context"
+
+	"github.com/mykytanikitenko/go-handle"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDMember is name of extension member of problem with trace id
+const TraceIDMember = "traceId"
+
+// TraceID adds trace id of span of ctx to problem,
+// use it as handler.ProblemConfig.Extend
+//
+// Example:
+//
+//	handler.WithProblems(handler.ProblemConfig{Writer: nethttp.Adapter{}, Extend: otelhandler.TraceID})
+func TraceID(ctx context.Context, problem *handler.Problem, err error) {
+	spanContext := trace.SpanContextFromContext(ctx)
+
+	if !spanContext.HasTraceID() {
+		return
+	}
+
+	if problem.Extensions == nil {
+		problem.Extensions = map[string]interface{}{}
+	}
+
+	problem.Extensions[TraceIDMember] = spanContext.TraceID().String()
+}
@@ -0,0 +1,62 @@
+package otelhandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/mykytanikitenko/go-handle/nethttp"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_TraceID_ExpectTraceIDOfSpan(t *testing.T) {
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	problem := handler.ProblemOf(errors.New("failed"))
+
+	TraceID(ctx, problem, nil)
+
+	assert.Equal(t, span.SpanContext().TraceID().String(), problem.Extensions[TraceIDMember])
+}
+
+func Test_TraceID_NoSpan_ExpectNoExtension(t *testing.T) {
+	problem := handler.ProblemOf(errors.New("failed"))
+
+	TraceID(context.Background(), problem, nil)
+
+	assert.Nil(t, problem.Extensions)
+}
+
+func Test_TraceID_WithHookAndProblems_ExpectTraceIDInBody(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	var notFound handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return nil, handler.NotFound("nope")
+	}
+
+	h, err := handler.New(handler.PipeGroup{notFound}, mockHandler{}, nethttp.Converter,
+		handler.WithHooks(New(provider)),
+		handler.WithProblems(handler.ProblemConfig{Writer: nethttp.Adapter{}, Extend: TraceID}),
+	)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.Handler().(http.HandlerFunc)(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	spans := exporter.GetSpans()
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, spans[len(spans)-1].SpanContext.TraceID().String(), body[TraceIDMember])
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Branch represents pipes of ParallelGroup what run concurrently with other branches
+type Branch struct {
+	// Fields of handler instance what branch writes.
+	// Field may be owned by one branch only
+	Fields []string
+
+	Pipes PipeGroup
+}
+
+// ParallelGroup represents group of branches what run concurrently.
+//
+// Each branch runs on its own shallow copy of handler instance, so branches
+// don't race. After all branches finish, fields owned by each branch are copied
+// to handler instance and execution continues with the next pipe.
+// Changes of other fields are discarded. Maps, slices and pointers are shared
+// by copies, so branches should not modify their contents in place.
+//
+// Pipe what returns AbortPipeGroup aborts its branch only, as if branch was
+// the whole handler. When any branch fails, context of other branches is
+// cancelled and the first error is returned, or all errors are joined
+// with errors.Join if JoinErrors is set. Hooks are called from goroutines
+// of branches, so they should be safe for concurrent use
+type ParallelGroup struct {
+	Branches []Branch
+
+	JoinErrors bool
+}
+
+// Parallel creates group of branches what run concurrently
+//
+// Example:
+//
+//	var ActionPipes = handler.PipeGroup{
+//		BindRequestPipe,
+//		handler.Parallel(
+//			handler.Branch{Fields: []string{"User"}, Pipes: handler.PipeGroup{LoadUser}},
+//			handler.Branch{Fields: []string{"Flags"}, Pipes: handler.PipeGroup{LoadFeatureFlags}},
+//		),
+//		handler.PipeArray{CallActionPipe},
+//	}
+func Parallel(branches ...Branch) ParallelGroup {
+	return ParallelGroup{Branches: branches}
+}
+
+// parallelPlan is compiled ParallelGroup
+type parallelPlan struct {
+	branches []*branchPlan
+	join     bool
+}
+
+// branchPlan is compiled Branch
+type branchPlan struct {
+	plan  []instruction
+	steps []Step
+
+	fields []string
+
+	// indexes of owned fields, nil if type of instance is unknown
+	index [][]int
+}
+
+// validateParallel checks branches and returns group with validated pipes
+func validateParallel(group ParallelGroup, path Path) (ParallelGroup, error) {
+	if len(group.Branches) == 0 {
+		return ParallelGroup{}, &TreeError{Path: path, Value: group, Err: ErrorPipeGroupEmpty}
+	}
+
+	validated := ParallelGroup{Branches: make([]Branch, len(group.Branches)), JoinErrors: group.JoinErrors}
+	owners := map[string]int{}
+
+	for i, branch := range group.Branches {
+		branchPath := childPath(path, i)
+
+		for _, field := range branch.Fields {
+			if owner, owned := owners[field]; owned {
+				return ParallelGroup{}, &TreeError{
+					Path:  branchPath,
+					Value: branch,
+					Err:   fmt.Errorf("%w: %s is owned by branch %d", ErrorParallelFieldOwned, field, owner),
+				}
+			}
+
+			owners[field] = i
+		}
+
+		pipes, err := validateGroup(branch.Pipes, branchPath)
+		if err != nil {
+			return ParallelGroup{}, err
+		}
+
+		validated.Branches[i] = Branch{Fields: branch.Fields, Pipes: pipes}
+	}
+
+	return validated, nil
+}
+
+// prepareParallel resolves owned fields of parallel groups of plan
+// and describes plans of branches for hooks
+func prepareParallel(plan []instruction, t reflect.Type, hook bool) error {
+	for _, i := range plan {
+		if i.op != opParallel {
+			continue
+		}
+
+		for bi, branch := range i.parallel.branches {
+			if t != nil {
+				branch.index = make([][]int, len(branch.fields))
+
+				for fi, name := range branch.fields {
+					field, ok := t.FieldByName(name)
+					if !ok {
+						return &TreeError{Path: childPath(i.path, bi), Value: name, Err: ErrorParallelField}
+					}
+
+					branch.index[fi] = field.Index
+				}
+			}
+
+			if hook {
+				branch.steps = steps(branch.plan, t)
+			}
+
+			if err := prepareParallel(branch.plan, t, hook); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parallel runs branches of parallel group and returns instance
+// with fields owned by branches copied from their instances
+func (e *execution) parallel(p *parallelPlan, instance reflect.Value) (reflect.Value, error) {
+	ctx, cancel := context.WithCancel(e.ctx)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+
+	errs := make([]error, len(p.branches))
+	panics := make([]interface{}, len(p.branches))
+
+	// executions are created here, so execution of handler doesn't escape to heap
+	branches := make([]execution, len(p.branches))
+
+	for i, branch := range p.branches {
+		branches[i] = execution{
+			args:     e.args,
+			instance: copyInstance(instance),
+			plan:     branch.plan,
+			hook:     e.hook,
+			steps:    branch.steps,
+			recovery: e.recovery,
+		}
+
+		branches[i].setContext(ctx)
+	}
+
+	for i := range branches {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			panics[i], errs[i] = branches[i].branch()
+
+			if errs[i] != nil && !p.join {
+				once.Do(func() {
+					first = errs[i]
+					cancel()
+				})
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	// panic without recovery is passed to goroutine of handler as *PipeError,
+	// what keeps stack of branch
+	for _, recovered := range panics {
+		if recovered != nil {
+			panic(recovered)
+		}
+	}
+
+	if p.join {
+		if err := errors.Join(errs...); err != nil {
+			return instance, err
+		}
+	} else if first != nil {
+		return instance, first
+	}
+
+	// pointed struct is updated in place, struct value is copied,
+	// because it may be not addressable
+	merged := instance
+	if instance.Kind() != reflect.Ptr {
+		merged = copyInstance(instance)
+	}
+
+	target := reflect.Indirect(merged)
+
+	for i, branch := range p.branches {
+		source := reflect.Indirect(branches[i].instance)
+
+		if source.Type() != target.Type() {
+			return instance, ErrorParallelInstanceType
+		}
+
+		for fi, name := range branch.fields {
+			// fields are resolved at runtime when type of instance was unknown on creation
+			if branch.index == nil {
+				field, ok := target.Type().FieldByName(name)
+				if !ok {
+					return instance, ErrorParallelField
+				}
+
+				target.FieldByIndex(field.Index).Set(source.FieldByIndex(field.Index))
+
+				continue
+			}
+
+			target.FieldByIndex(branch.index[fi]).Set(source.FieldByIndex(branch.index[fi]))
+		}
+	}
+
+	return merged, nil
+}
+
+// branch runs plan of branch, its final instance is left in execution.
+// Panic what is not recovered by WithRecovery is returned as *PipeError
+func (e *execution) branch() (panicked interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			panicked = e.pipeError(recovered)
+		}
+	}()
+
+	if e.recovery {
+		defer e.recover(&err)
+	}
+
+	err = e.run()
+	e.unwind(err)
+
+	return nil, err
+}
+
+// copyInstance returns shallow copy of struct or pointer to struct
+func copyInstance(instance reflect.Value) reflect.Value {
+	if instance.Kind() == reflect.Ptr {
+		copied := reflect.New(instance.Type().Elem())
+		copied.Elem().Set(instance.Elem())
+
+		return copied
+	}
+
+	copied := reflect.New(instance.Type()).Elem()
+	copied.Set(instance)
+
+	return copied
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockParallel struct {
+	User    string
+	Flags   string
+	Scratch int
+}
+
+// setPipe sets field of mockParallel
+func setPipe(field, value string) Pipe {
+	return func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		reflect.Indirect(v).FieldByName(field).SetString(value)
+		reflect.Indirect(v).FieldByName("Scratch").SetInt(1)
+
+		return ContinuePipeGroup(v), nil
+	}
+}
+
+// runParallel runs pipes and returns instance seen by the last pipe
+func runParallel(t *testing.T, pipes PipeGroup, instance interface{}, options ...Option) (mockParallel, error) {
+	var result mockParallel
+
+	var last Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		result = reflect.Indirect(v).Interface().(mockParallel)
+
+		return ContinuePipeGroup(v), nil
+	}
+
+	h, err := New(append(pipes, last), instance, converterMock, options...)
+	assert.NoError(t, err)
+
+	return result, h.Handler().(func(*mockContext) error)(&mockContext{})
+}
+
+func Test_Handler_Parallel_ExpectOwnedFieldsMerged(t *testing.T) {
+	// each branch waits for another, so they can't pass sequentially
+	user, flags := make(chan struct{}), make(chan struct{})
+
+	meet := func(own, other chan struct{}) Pipe {
+		return func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+			close(own)
+
+			select {
+			case <-other:
+			case <-time.After(time.Second):
+				return nil, errors.New("branches don't run concurrently")
+			}
+
+			return ContinuePipeGroup(v), nil
+		}
+	}
+
+	result, err := runParallel(t, PipeGroup{
+		Parallel(
+			Branch{Fields: []string{"User"}, Pipes: PipeGroup{meet(user, flags), []Pipe{setPipe("User", "user")}}},
+			Branch{Fields: []string{"Flags"}, Pipes: PipeGroup{meet(flags, user), []Pipe{setPipe("Flags", "flags")}}},
+		),
+	}, mockParallel{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, mockParallel{User: "user", Flags: "flags"}, result, "changes of not owned fields are discarded")
+}
+
+func Test_Handler_Parallel_PointerInstance_ExpectMergedInPlace(t *testing.T) {
+	result, err := runParallel(t, PipeGroup{
+		Parallel(
+			Branch{Fields: []string{"User"}, Pipes: PipeGroup{[]Pipe{setPipe("User", "user")}}},
+		),
+	}, &mockParallel{Flags: "flags"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, mockParallel{User: "user", Flags: "flags"}, result)
+}
+
+func Test_Handler_Parallel_BranchAborted_ExpectOtherBranchesAndNextPipesExecuted(t *testing.T) {
+	var abort Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, nil
+	}
+
+	result, err := runParallel(t, PipeGroup{
+		Parallel(
+			Branch{Fields: []string{"User"}, Pipes: PipeGroup{[]Pipe{setPipe("User", "user")}, abort, setPipe("User", "unreachable")}},
+			Branch{Fields: []string{"Flags"}, Pipes: PipeGroup{[]Pipe{setPipe("Flags", "flags")}}},
+		),
+	}, mockParallel{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, mockParallel{User: "user", Flags: "flags"}, result)
+}
+
+func Test_Handler_Parallel_BranchFails_ExpectOtherBranchesCanceledAndFirstError(t *testing.T) {
+	mockError := errors.New("user not found")
+
+	// failing branch waits for another to start, otherwise it may be canceled before its first pipe
+	started := make(chan struct{})
+
+	var failing Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		<-started
+
+		return nil, mockError
+	}
+
+	var canceled error
+
+	var waiting ContextPipe = func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		close(started)
+
+		select {
+		case <-ctx.Done():
+			canceled = ctx.Err()
+		case <-time.After(time.Second):
+		}
+
+		return ContinuePipeGroup(v), nil
+	}
+
+	var executed bool
+
+	_, err := runParallel(t, PipeGroup{
+		Parallel(
+			Branch{Pipes: PipeGroup{failing}},
+			Branch{Pipes: PipeGroup{waiting, func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+				executed = true
+
+				return ContinuePipeGroup(v), nil
+			}}},
+		),
+	}, mockParallel{})
+
+	assert.ErrorIs(t, err, mockError)
+	assert.Equal(t, context.Canceled, canceled)
+	assert.False(t, executed, "canceled branch stops before the next pipe")
+}
+
+func Test_Handler_Parallel_JoinErrors_ExpectAllErrorsJoined(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+
+	fail := func(err error) Pipe {
+		return func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+			return nil, err
+		}
+	}
+
+	group := Parallel(Branch{Pipes: PipeGroup{fail(first)}}, Branch{Pipes: PipeGroup{fail(second)}})
+	group.JoinErrors = true
+
+	_, err := runParallel(t, PipeGroup{group}, mockParallel{})
+
+	assert.ErrorIs(t, err, first)
+	assert.ErrorIs(t, err, second)
+}
+
+func Test_Handler_Parallel_BranchPanics_ExpectPipeErrorWithStack(t *testing.T) {
+	var panicking Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		panic("boom")
+	}
+
+	pipes := PipeGroup{Parallel(Branch{Pipes: PipeGroup{nopPipe}}, Branch{Pipes: PipeGroup{panicking}})}
+
+	_, err := runParallel(t, pipes, mockParallel{}, WithRecovery())
+
+	var pipeErr *PipeError
+	assert.ErrorAs(t, err, &pipeErr)
+	assert.Equal(t, "boom", pipeErr.Recovered)
+	assert.Equal(t, Path{0, 1, 0}, pipeErr.Path)
+
+	defer func() {
+		pipeErr, ok := recover().(*PipeError)
+
+		assert.True(t, ok, "panic of branch is passed to goroutine of handler as *PipeError")
+		assert.Equal(t, "boom", pipeErr.Recovered)
+		assert.Equal(t, Path{0, 1, 0}, pipeErr.Path)
+		assert.Contains(t, string(pipeErr.Stack), "Test_Handler_Parallel_BranchPanics_ExpectPipeErrorWithStack.func1", "stack of branch is kept")
+	}()
+
+	_, _ = runParallel(t, pipes, mockParallel{})
+
+	t.Error("expected panic")
+}
+
+func Test_New_ParallelInvalid_ExpectError(t *testing.T) {
+	cases := map[string]struct {
+		group ParallelGroup
+		err   error
+	}{
+		"no branches":   {Parallel(), ErrorPipeGroupEmpty},
+		"empty branch":  {Parallel(Branch{}), ErrorPipeGroupEmpty},
+		"owned twice":   {Parallel(Branch{Fields: []string{"User"}, Pipes: PipeGroup{nopPipe}}, Branch{Fields: []string{"User"}, Pipes: PipeGroup{nopPipe}}), ErrorParallelFieldOwned},
+		"unknown field": {Parallel(Branch{Fields: []string{"Unknown"}, Pipes: PipeGroup{nopPipe}}), ErrorParallelField},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(PipeGroup{c.group}, mockParallel{}, converterMock)
+
+			assert.ErrorIs(t, err, c.err)
+		})
+	}
+}
+
+func Test_Handler_Parallel_WithHooks_ExpectBranchesObserved(t *testing.T) {
+	hook := &recordingHook{}
+
+	_, err := runParallel(t, PipeGroup{Parallel(Branch{Pipes: PipeGroup{nopPipe}})}, mockParallel{}, WithHooks(hook))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"enter group",
+		"enter parallel[0]",
+		"enter group[0][0]",
+		"before [0][0][0] in [0][0]",
+		"after [0][0][0] aborted=false err=<nil>",
+		"exit group[0][0] aborted=false err=<nil>",
+		"exit parallel[0] aborted=false err=<nil>",
+		"before [1] in ",
+		"after [1] aborted=false err=<nil>",
+		"exit group aborted=false err=<nil>",
+	}, hook.events)
+}
@@ -27,7 +27,19 @@ type Pipe func(v reflect.Value, args ...interface{}) (*reflect.Value, error)
 //
 // Example:
 //    var ActionPipes = handler.PipeGroup{
-//      []handler.Pipe{BindRequestPipe, ValidateRequestPipe},
-//      []handler.Pipe{CallActionPipe, NoActionsPipe},
+//      handler.PipeArray{BindRequestPipe, ValidateRequestPipe},
+//      handler.PipeArray{CallActionPipe, NoActionsPipe},
 //    }
 type PipeGroup []interface{}
+
+// PipeArray is []Pipe what may contain named pipes and context pipes.
+// Like in []Pipe, pipe what returns AbortPipeGroup skips the rest of array
+// and value returned by pipe replaces instance
+//
+// Example:
+//
+//	var ActionPipes = handler.PipeGroup{
+//		std.BindRequest,
+//		handler.PipeArray{std.CallAction, std.NoActions},
+//	}
+type PipeArray []interface{}
@@ -0,0 +1,433 @@
+// Package pipes provides standard framework-agnostic pipes
+// for handlers declared as structs like
+//
+//	type GetArticle struct {
+//		Request struct {
+//			ID int `param:"id"`
+//		}
+//		Response Article
+//		Services struct {
+//			ArticlesRepo
+//		}
+//	}
+//
+//	func (action *GetArticle) Action() (interface{}, error) {
+//		return action.Services.ArticlesRepo.Get(action.Request.ID)
+//	}
+//
+// Framework specific parts are provided by Adapter
+package pipes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+var (
+	ErrorNotAddressable = fmt.Errorf("pipes: not addressable field")
+	ErrorInvalidAction  = fmt.Errorf("pipes: action should be func() (interface{}, error)")
+	ErrorNoActions      = fmt.Errorf("pipes: can't process action: no action methods")
+	ErrorServicesType   = fmt.Errorf("pipes: services not assignable to services field")
+)
+
+// Adapter adapts pipes to framework.
+// args are arguments passed to generic handler by converter
+type Adapter interface {
+	// Bind binds request to dst
+	Bind(dst interface{}, args ...interface{}) error
+
+	// JSON writes v as JSON response with status,
+	// v is nil for status 204
+	JSON(status int, v interface{}, args ...interface{}) error
+
+	// Param returns path parameter by name
+	Param(name string, args ...interface{}) string
+}
+
+// ProblemAdapter may be implemented by Adapter to write problem details
+// with application/problem+json content type, Adapter.JSON is used otherwise
+type ProblemAdapter interface {
+	Problem(problem *handler.Problem, args ...interface{}) error
+}
+
+// Validator may be implemented by request field type,
+// ValidateRequest pipe calls it if Config.Validate is nil
+type Validator interface {
+	Validate() error
+}
+
+// Config configures standard pipes.
+// Zero values are replaced with defaults
+type Config struct {
+	// Name of field what request is bound to, "Request" by default
+	RequestField string
+
+	// Name of field what is rendered if action returns nil, "Response" by default
+	ResponseField string
+
+	// Name of field what Services value is assigned to, "Services" by default
+	ServicesField string
+
+	// Name of action method, "Action" by default
+	ActionMethod string
+
+	// Services assigned to services field of each handler instance.
+	// To resolve services from container use handler.Inject option instead
+	Services interface{}
+
+	// Authorize checks that request has scopes declared by handler.Endpoint
+	// field of handler type, handlers without scopes are not checked
+	Authorize func(scopes []string, args ...interface{}) error
+
+	// Validate validates request field, Validator is used if nil
+	Validate func(request interface{}) error
+
+	// Error converts bind, validation and action errors
+	// to response body, {"error": "message"} by default.
+	// Errors of status 5xx are passed with kind and message of *handler.Error only,
+	// so their causes aren't written to client
+	Error func(err error) interface{}
+
+	// Problems makes errors written as RFC 7807 problem details, see handler.NewProblem.
+	// Error is not used then
+	Problems bool
+}
+
+// Set represents set of standard pipes.
+// Errors what are *handler.Error are written with status of their kind
+// instead of statuses documented for pipes
+//
+// Example:
+//
+//	var std = pipes.New(EchoAdapter{}, pipes.Config{})
+//
+//	var ActionPipes = handler.PipeGroup{
+//		std.BindRequest,
+//		std.ValidateRequest,
+//		handler.PipeArray{std.CallAction, std.NoActions},
+//	}
+type Set struct {
+	// InjectServices assigns Config.Services to services field,
+	// handler.Inject option resolves services from container instead
+	InjectServices handler.NamedPipe
+
+	// Authorize calls Config.Authorize with scopes of handler type.
+	// Authorization error is written with status 403
+	Authorize handler.NamedPipe
+
+	// BindRequest binds request to request field using Adapter.Bind,
+	// then fields tagged with `param:"name"` are set from Adapter.Param.
+	// Bind error is written with status 400
+	BindRequest handler.NamedPipe
+
+	// ValidateRequest validates request field.
+	// Validation error is written with status 400
+	ValidateRequest handler.NamedPipe
+
+	// CallAction calls action method of handler.
+	//
+	// Action error is written with status of its kind, so errors what are not
+	// *handler.Error are written with status 500 and without their message. Result of action is written
+	// as JSON, if action returns nil result, response field is written.
+	// If handler has no action method, next pipe is called, otherwise
+	// other pipes of the array are skipped
+	CallAction handler.NamedPipe
+
+	// NoActions returns ErrorNoActions, place it after CallAction
+	// to require action method
+	NoActions handler.NamedPipe
+
+	// Render writes response field as JSON,
+	// or responds with status 204 if there is no such field
+	Render handler.NamedPipe
+
+	adapter Adapter
+	config  Config
+
+	// route metadata of handler types checked by Authorize, reflect.Type -> handler.Metadata
+	metadata sync.Map
+}
+
+// New creates standard pipes for adapter
+func New(adapter Adapter, config Config) *Set {
+	if config.RequestField == "" {
+		config.RequestField = "Request"
+	}
+
+	if config.ResponseField == "" {
+		config.ResponseField = "Response"
+	}
+
+	if config.ServicesField == "" {
+		config.ServicesField = "Services"
+	}
+
+	if config.ActionMethod == "" {
+		config.ActionMethod = "Action"
+	}
+
+	if config.Error == nil {
+		config.Error = func(err error) interface{} {
+			return map[string]string{"error": err.Error()}
+		}
+	}
+
+	s := &Set{
+		adapter: adapter,
+		config:  config,
+	}
+
+	s.InjectServices = handler.Named("InjectServices", s.injectServices,
+		handler.Description("assigns services to "+config.ServicesField+" field"))
+	s.Authorize = handler.Named("Authorize", s.authorize,
+		handler.Description("checks scopes of Endpoint field"))
+	s.BindRequest = handler.Named("BindRequest", s.bindRequest,
+		handler.Description("binds request to "+config.RequestField+" field"), handler.Tags("http"))
+	s.ValidateRequest = handler.Named("ValidateRequest", s.validateRequest,
+		handler.Description("validates "+config.RequestField+" field"))
+	s.CallAction = handler.Named("CallAction", s.callAction,
+		handler.Description("calls "+config.ActionMethod+" method and writes its result"), handler.Tags("http"))
+	s.NoActions = handler.Named("NoActions", s.noActions,
+		handler.Description("fails if there is no "+config.ActionMethod+" method"))
+	s.Render = handler.Named("Render", s.render,
+		handler.Description("writes "+config.ResponseField+" field"), handler.Tags("http"))
+
+	return s
+}
+
+// Group returns standard pipe group.
+//
+// Inject, authorize, bind and validation pipes are placed directly in group,
+// so their failure stops execution of the whole group
+func (s *Set) Group() handler.PipeGroup {
+	return handler.PipeGroup{
+		s.InjectServices,
+		s.Authorize,
+		s.BindRequest,
+		s.ValidateRequest,
+		handler.PipeArray{s.CallAction, s.Render},
+	}
+}
+
+func (s *Set) injectServices(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	field := reflect.Indirect(v).FieldByName(s.config.ServicesField)
+
+	if !field.IsValid() || s.config.Services == nil {
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	if !field.CanSet() {
+		return handler.AbortPipeGroup, ErrorNotAddressable
+	}
+
+	services := reflect.ValueOf(s.config.Services)
+
+	if !services.Type().AssignableTo(field.Type()) {
+		return handler.AbortPipeGroup, ErrorServicesType
+	}
+
+	field.Set(services)
+
+	return handler.ContinuePipeGroup(v), nil
+}
+
+func (s *Set) authorize(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	if s.config.Authorize == nil {
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	metadata, err := s.metadataOf(v.Type())
+	if err != nil {
+		return handler.AbortPipeGroup, err
+	}
+
+	if len(metadata.Scopes) == 0 {
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	if err := s.config.Authorize(metadata.Scopes, args...); err != nil {
+		return handler.AbortPipeGroup, s.writeError(err, http.StatusForbidden, args)
+	}
+
+	return handler.ContinuePipeGroup(v), nil
+}
+
+// metadataOf returns route metadata of handler type, it's computed once per type
+func (s *Set) metadataOf(t reflect.Type) (handler.Metadata, error) {
+	if metadata, ok := s.metadata.Load(t); ok {
+		return metadata.(handler.Metadata), nil
+	}
+
+	metadata, err := handler.MetadataOf(t)
+	if err != nil {
+		return handler.Metadata{}, err
+	}
+
+	s.metadata.Store(t, metadata)
+
+	return metadata, nil
+}
+
+func (s *Set) bindRequest(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	field := reflect.Indirect(v).FieldByName(s.config.RequestField)
+
+	if !field.IsValid() {
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	if !field.CanAddr() {
+		return handler.AbortPipeGroup, ErrorNotAddressable
+	}
+
+	if err := s.adapter.Bind(field.Addr().Interface(), args...); err != nil {
+		return handler.AbortPipeGroup, s.writeError(err, http.StatusBadRequest, args)
+	}
+
+	if err := s.bindParams(reflect.Indirect(field), args); err != nil {
+		return handler.AbortPipeGroup, s.writeError(err, http.StatusBadRequest, args)
+	}
+
+	return handler.ContinuePipeGroup(v), nil
+}
+
+// bindParams sets fields tagged with `param:"name"` from path parameters
+func (s *Set) bindParams(request reflect.Value, args []interface{}) error {
+	if request.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < request.NumField(); i++ {
+		field := request.Type().Field(i)
+
+		name, ok := field.Tag.Lookup("param")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		value := s.adapter.Param(name, args...)
+		if value == "" {
+			continue
+		}
+
+		if err := SetString(request.Field(i), value); err != nil {
+			return fmt.Errorf("param %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Set) validateRequest(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	field := reflect.Indirect(v).FieldByName(s.config.RequestField)
+
+	if !field.IsValid() || !field.CanAddr() {
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	request := field.Addr().Interface()
+
+	var err error
+
+	if s.config.Validate != nil {
+		err = s.config.Validate(request)
+	} else if validator, ok := request.(Validator); ok {
+		err = validator.Validate()
+	}
+
+	if err != nil {
+		return handler.AbortPipeGroup, s.writeError(err, http.StatusBadRequest, args)
+	}
+
+	return handler.ContinuePipeGroup(v), nil
+}
+
+func (s *Set) callAction(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	action := v.MethodByName(s.config.ActionMethod)
+
+	if !action.IsValid() && v.Kind() != reflect.Ptr && v.CanAddr() {
+		action = v.Addr().MethodByName(s.config.ActionMethod)
+	}
+
+	if !action.IsValid() {
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	actionFunc, ok := action.Interface().(func() (interface{}, error))
+	if !ok {
+		return handler.AbortPipeGroup, ErrorInvalidAction
+	}
+
+	result, err := actionFunc()
+	if err != nil {
+		return handler.AbortPipeGroup, s.writeError(err, handler.KindOf(err).Status(), args)
+	}
+
+	if result == nil {
+		return handler.AbortPipeGroup, s.writeResponse(v, args)
+	}
+
+	return handler.AbortPipeGroup, s.adapter.JSON(http.StatusOK, result, args...)
+}
+
+func (s *Set) noActions(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	return handler.AbortPipeGroup, ErrorNoActions
+}
+
+func (s *Set) render(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+	return handler.ContinuePipeGroup(v), s.writeResponse(v, args)
+}
+
+func (s *Set) writeResponse(v reflect.Value, args []interface{}) error {
+	field := reflect.Indirect(v).FieldByName(s.config.ResponseField)
+
+	if !field.IsValid() {
+		return s.adapter.JSON(http.StatusNoContent, nil, args...)
+	}
+
+	return s.adapter.JSON(http.StatusOK, field.Interface(), args...)
+}
+
+// writeError writes err with status of its kind if it's *handler.Error,
+// or with fallback status otherwise
+func (s *Set) writeError(err error, fallback int, args []interface{}) error {
+	status := fallback
+
+	var e *handler.Error
+	if errors.As(err, &e) {
+		status = e.Kind.Status()
+	}
+
+	if status >= http.StatusInternalServerError {
+		err = serverError(err)
+	}
+
+	if !s.config.Problems {
+		return s.adapter.JSON(status, s.config.Error(err), args...)
+	}
+
+	problem := handler.NewProblem(status, err)
+
+	if adapter, ok := s.adapter.(ProblemAdapter); ok {
+		return adapter.Problem(problem, args...)
+	}
+
+	return s.adapter.JSON(status, problem, args...)
+}
+
+// serverError returns error with kind, message and fields of err only,
+// so causes of server errors like database errors aren't written to client
+func serverError(err error) error {
+	safe := handler.NewError(handler.KindOf(err), "")
+
+	var e *handler.Error
+	if errors.As(err, &e) {
+		safe.Message, safe.Fields = e.Message, e.Fields
+	}
+
+	return safe
+}
@@ -0,0 +1,378 @@
+package pipes
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	mockAdapter struct {
+		params  map[string]string
+		bindErr error
+
+		status int
+		body   interface{}
+	}
+
+	mockRequest struct {
+		ID    int `param:"id"`
+		Title string
+	}
+
+	mockServices struct {
+		Name string
+	}
+
+	mockAction struct {
+		Request  mockRequest
+		Services *mockServices
+	}
+
+	mockPointerAction struct {
+		Request mockRequest
+	}
+
+	mockFailingAction struct{}
+
+	mockInvalidAction struct{}
+
+	mockResponseAction struct {
+		Response string
+	}
+)
+
+func (a *mockAdapter) Bind(dst interface{}, args ...interface{}) error {
+	if a.bindErr != nil {
+		return a.bindErr
+	}
+
+	dst.(*mockRequest).Title = "bound"
+
+	return nil
+}
+
+func (a *mockAdapter) JSON(status int, v interface{}, args ...interface{}) error {
+	a.status = status
+	a.body = v
+
+	return nil
+}
+
+func (a *mockAdapter) Param(name string, args ...interface{}) string {
+	return a.params[name]
+}
+
+func (r *mockRequest) Validate() error {
+	if r.ID < 0 {
+		return errors.New("negative id")
+	}
+
+	return nil
+}
+
+func (a mockAction) Action() (interface{}, error) {
+	return a.Request, nil
+}
+
+func (a *mockPointerAction) Action() (interface{}, error) {
+	return a.Request.ID, nil
+}
+
+func (a mockFailingAction) Action() (interface{}, error) {
+	return nil, errors.New("action failed")
+}
+
+func (a mockInvalidAction) Action() error {
+	return nil
+}
+
+func (a mockResponseAction) Action() (interface{}, error) {
+	return nil, nil
+}
+
+func run(t *testing.T, pipes handler.PipeGroup, instance interface{}) error {
+	h, err := handler.New(pipes, instance, func(f handler.GenericHandlerFunc) interface{} {
+		return f
+	})
+	assert.NoError(t, err)
+
+	return h.Handler().(handler.GenericHandlerFunc)()
+}
+
+func Test_Set_Group_ExpectBoundValidatedAndRendered(t *testing.T) {
+	adapter := &mockAdapter{params: map[string]string{"id": "42"}}
+
+	err := run(t, New(adapter, Config{}).Group(), mockAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, adapter.status)
+	assert.Equal(t, mockRequest{ID: 42, Title: "bound"}, adapter.body)
+}
+
+func Test_Set_BindRequest_BindError_ExpectBadRequestAndAbort(t *testing.T) {
+	adapter := &mockAdapter{bindErr: errors.New("bad body")}
+
+	err := run(t, New(adapter, Config{}).Group(), mockAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, adapter.status)
+	assert.Equal(t, map[string]string{"error": "bad body"}, adapter.body)
+}
+
+func Test_Set_BindRequest_InvalidParam_ExpectBadRequest(t *testing.T) {
+	adapter := &mockAdapter{params: map[string]string{"id": "abc"}}
+
+	err := run(t, New(adapter, Config{}).Group(), mockAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, adapter.status)
+}
+
+func Test_Set_ValidateRequest_ValidatorFails_ExpectBadRequest(t *testing.T) {
+	adapter := &mockAdapter{params: map[string]string{"id": "-1"}}
+
+	err := run(t, New(adapter, Config{}).Group(), mockAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, adapter.status)
+	assert.Equal(t, map[string]string{"error": "negative id"}, adapter.body)
+}
+
+func Test_Set_ValidateRequest_CustomValidate_ExpectCalled(t *testing.T) {
+	adapter := &mockAdapter{}
+
+	var validated interface{}
+
+	set := New(adapter, Config{
+		Validate: func(request interface{}) error {
+			validated = request
+
+			return errors.New("custom")
+		},
+		Error: func(err error) interface{} {
+			return err.Error()
+		},
+	})
+
+	err := run(t, set.Group(), mockAction{})
+
+	assert.NoError(t, err)
+	assert.IsType(t, &mockRequest{}, validated)
+	assert.Equal(t, http.StatusBadRequest, adapter.status)
+	assert.Equal(t, "custom", adapter.body)
+}
+
+func Test_Set_CustomFieldNames_ExpectUsed(t *testing.T) {
+	type customAction struct {
+		Input  mockRequest
+		Output string
+	}
+
+	adapter := &mockAdapter{}
+
+	set := New(adapter, Config{RequestField: "Input", ResponseField: "Output"})
+
+	var pipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		assert.Equal(t, "bound", v.FieldByName("Input").FieldByName("Title").String())
+		v.FieldByName("Output").SetString("output")
+
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	err := run(t, handler.PipeGroup{handler.PipeArray{set.BindRequest, pipe, set.Render}}, customAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, adapter.status)
+	assert.Equal(t, "output", adapter.body)
+}
+
+func Test_Set_InjectServices_ExpectAssigned(t *testing.T) {
+	services := &mockServices{Name: "services"}
+
+	set := New(&mockAdapter{}, Config{Services: services})
+
+	var injected *mockServices
+
+	var pipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		injected = v.Interface().(mockAction).Services
+
+		return handler.ContinuePipeGroup(v), nil
+	}
+
+	err := run(t, handler.PipeGroup{set.InjectServices, pipe}, mockAction{})
+
+	assert.NoError(t, err)
+	assert.Same(t, services, injected)
+}
+
+func Test_Set_InjectServices_WrongType_ExpectError(t *testing.T) {
+	set := New(&mockAdapter{}, Config{Services: "not services"})
+
+	err := run(t, handler.PipeGroup{set.InjectServices}, mockAction{})
+
+	assert.Equal(t, ErrorServicesType, err)
+}
+
+func Test_Set_CallAction_PointerReceiver_ExpectCalled(t *testing.T) {
+	adapter := &mockAdapter{params: map[string]string{"id": "7"}}
+
+	err := run(t, New(adapter, Config{}).Group(), mockPointerAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, adapter.body)
+}
+
+func Test_Set_CallAction_ActionError_ExpectInternalServerErrorWithoutMessage(t *testing.T) {
+	adapter := &mockAdapter{}
+
+	err := run(t, New(adapter, Config{}).Group(), mockFailingAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, adapter.status)
+	assert.Equal(t, map[string]string{"error": "handler: internal"}, adapter.body)
+
+	err = run(t, New(adapter, Config{Problems: true}).Group(), mockFailingAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, adapter.status)
+	assert.Equal(t, &handler.Problem{Title: "Internal Server Error", Status: http.StatusInternalServerError}, adapter.body)
+}
+
+func Test_Set_CallAction_InvalidSignature_ExpectError(t *testing.T) {
+	err := run(t, New(&mockAdapter{}, Config{}).Group(), mockInvalidAction{})
+
+	assert.Equal(t, ErrorInvalidAction, err)
+}
+
+func Test_Set_CallAction_NilResult_ExpectResponseRendered(t *testing.T) {
+	adapter := &mockAdapter{}
+
+	err := run(t, New(adapter, Config{}).Group(), mockResponseAction{Response: "response"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, adapter.status)
+	assert.Equal(t, "response", adapter.body)
+}
+
+func Test_Set_NoActions_ExpectError(t *testing.T) {
+	set := New(&mockAdapter{}, Config{})
+
+	err := run(t, handler.PipeGroup{handler.PipeArray{set.CallAction, set.NoActions}}, struct{}{})
+
+	assert.Equal(t, ErrorNoActions, err)
+}
+
+func Test_Set_Render_NoResponseField_ExpectNoContent(t *testing.T) {
+	adapter := &mockAdapter{}
+
+	err := run(t, New(adapter, Config{}).Group(), struct{}{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, adapter.status)
+	assert.Nil(t, adapter.body)
+}
+
+type mockScopedAction struct {
+	handler.Endpoint `method:"DELETE" path:"/articles/:id" scope:"articles:write,admin"`
+
+	mockResponseAction
+}
+
+func Test_Set_Authorize_ExpectScopesOfEndpointChecked(t *testing.T) {
+	adapter := &mockAdapter{}
+
+	var checked []string
+
+	set := New(adapter, Config{
+		Authorize: func(scopes []string, args ...interface{}) error {
+			checked = scopes
+
+			return errors.New("forbidden")
+		},
+	})
+
+	err := run(t, set.Group(), mockScopedAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"articles:write", "admin"}, checked)
+	assert.Equal(t, http.StatusForbidden, adapter.status)
+
+	_, cached := set.metadata.Load(reflect.TypeOf(mockScopedAction{}))
+	assert.True(t, cached, "metadata is computed once per type")
+
+	// handlers without scopes are not checked
+	checked = nil
+
+	err = run(t, set.Group(), mockResponseAction{Response: "response"})
+
+	assert.NoError(t, err)
+	assert.Nil(t, checked)
+	assert.Equal(t, http.StatusOK, adapter.status)
+}
+
+func Test_Set_Group_ExpectNamedPipes(t *testing.T) {
+	set := New(&mockAdapter{}, Config{})
+
+	assert.Equal(t, `group
+  [0] InjectServices: assigns services to Services field (abort: stop)
+  [1] Authorize: checks scopes of Endpoint field (abort: stop)
+  [2] BindRequest [http]: binds request to Request field (abort: stop)
+  [3] ValidateRequest: validates Request field (abort: stop)
+  [4] array
+    [4][0] CallAction [http]: calls Action method and writes its result (abort: skip to end)
+    [4][1] Render [http]: writes Response field (abort: skip to end)
+`, handler.Dump(set.Group()))
+}
+
+type mockNotFoundAction struct{}
+
+func (a mockNotFoundAction) Action() (interface{}, error) {
+	return nil, handler.NotFound("article not found")
+}
+
+func Test_Set_CallAction_TypedError_ExpectStatusOfKind(t *testing.T) {
+	adapter := &mockAdapter{}
+
+	err := run(t, New(adapter, Config{}).Group(), mockNotFoundAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, adapter.status)
+	assert.Equal(t, map[string]string{"error": "handler: not found: article not found"}, adapter.body)
+}
+
+func Test_Set_Problems_ExpectProblemDetails(t *testing.T) {
+	adapter := &mockAdapter{}
+
+	err := run(t, New(adapter, Config{Problems: true}).Group(), mockNotFoundAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, adapter.status)
+	assert.Equal(t, &handler.Problem{Title: "Not Found", Status: http.StatusNotFound, Detail: "article not found"}, adapter.body)
+}
+
+type mockProblemAdapter struct {
+	mockAdapter
+
+	problem *handler.Problem
+}
+
+func (a *mockProblemAdapter) Problem(problem *handler.Problem, args ...interface{}) error {
+	a.problem = problem
+
+	return nil
+}
+
+func Test_Set_Problems_ProblemAdapter_ExpectProblemWritten(t *testing.T) {
+	adapter := &mockProblemAdapter{mockAdapter: mockAdapter{bindErr: errors.New("bad body")}}
+
+	err := run(t, New(adapter, Config{Problems: true}).Group(), mockAction{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &handler.Problem{Title: "Bad Request", Status: http.StatusBadRequest, Detail: "bad body"}, adapter.problem)
+	assert.Zero(t, adapter.status)
+}
@@ -0,0 +1,52 @@
+package pipes
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// SetString parses s to value of field kind and sets it.
+// Supports strings, integers, floats and bools
+func SetString(field reflect.Value, s string) error {
+	if !field.CanSet() {
+		return ErrorNotAddressable
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"time"
+)
+
+// opcode represents kind of plan instruction
+type opcode uint8
+
+const (
+	// opPipe calls pipe
+	opPipe opcode = iota
+
+	// opEnter enters group of pipes
+	opEnter
+
+	// opExit exits group of pipes
+	opExit
+
+	// opParallel runs branches of ParallelGroup
+	opParallel
+
+	// opSwitch jumps to group of IfGroup or SwitchGroup chosen by predicates
+	opSwitch
+
+	// opJump jumps to exit of IfGroup or SwitchGroup after chosen group
+	opJump
+)
+
+// halt is abort target what stops execution of the whole plan
+const halt = -1
+
+// instruction represents single step of compiled PipeGroup tree
+type instruction struct {
+	op opcode
+
+	// pipe to call, one of them is set for opPipe
+	pipe        Pipe
+	contextPipe ContextPipe
+
+	// index of instruction to jump to when pipe returns AbortPipeGroup.
+	//
+	// Pipe placed in []Pipe or PipeArray jumps to exit of the array, pipe placed directly
+	// in PipeGroup halts, because aborted group aborts groups what contain it
+	abort int
+
+	// whether value returned by pipe replaces instance,
+	// only pipes placed in []Pipe or PipeArray replace it
+	replace bool
+
+	// timeout of group for opEnter and opExit, zero if group has no timeout
+	timeout time.Duration
+
+	// compiled branches for opParallel
+	parallel *parallelPlan
+
+	// conditions of opSwitch and indexes of instructions to jump to,
+	// the last target is taken when no condition is true
+	predicates []Condition
+	targets    []int
+
+	// index of instruction to jump to for opJump
+	jump int
+
+	// path of pipe or group in PipeGroup tree
+	path Path
+
+	// original element of PipeGroup tree
+	node interface{}
+}
+
+// compile compiles validated PipeGroup tree into flat list of instructions,
+// so executor doesn't need to traverse tree on each request.
+//
+// Groups are entered and exited only when they have timeout or observed
+// is true, otherwise their pipes are placed in plan without opEnter and opExit
+func compile(pipes PipeGroup, observed bool) []instruction {
+	c := compiler{observed: observed}
+
+	c.node(pipes, Path{})
+
+	return c.plan
+}
+
+type compiler struct {
+	plan []instruction
+
+	// whether groups are observed by hooks
+	observed bool
+}
+
+func (c *compiler) emit(i instruction) int {
+	c.plan = append(c.plan, i)
+
+	return len(c.plan) - 1
+}
+
+// group emits opEnter or opExit of group if group has timeout or is observed,
+// returns index of instruction what follows the entered or exited group
+func (c *compiler) group(i instruction) int {
+	if i.timeout > 0 || c.observed {
+		return c.emit(i)
+	}
+
+	return len(c.plan)
+}
+
+func (c *compiler) node(node interface{}, path Path) {
+	switch pipe := node.(type) {
+	case Pipe, ContextPipe, NamedPipe:
+		c.emit(pipeInstruction(pipe, halt, false, path))
+	case []Pipe:
+		pipes := make([]interface{}, len(pipe))
+
+		for i, p := range pipe {
+			pipes[i] = p
+		}
+
+		c.array(node, pipes, path)
+	case PipeArray:
+		c.array(node, pipe, path)
+	case PipeGroup:
+		c.group(instruction{op: opEnter, path: path, node: node})
+		c.children(pipe, path)
+		c.group(instruction{op: opExit, path: path, node: node})
+	case TimeoutGroup:
+		c.group(instruction{op: opEnter, timeout: pipe.Timeout, path: path, node: node})
+		c.children(pipe.Pipes, path)
+		c.group(instruction{op: opExit, timeout: pipe.Timeout, path: path, node: node})
+	case ParallelGroup:
+		// branches are compiled into separate plans, because they run on their own executions
+		parallel := &parallelPlan{branches: make([]*branchPlan, len(pipe.Branches)), join: pipe.JoinErrors}
+
+		for i, branch := range pipe.Branches {
+			bc := compiler{observed: c.observed}
+
+			bc.node(branch.Pipes, childPath(path, i))
+
+			parallel.branches[i] = &branchPlan{plan: bc.plan, fields: branch.Fields}
+		}
+
+		c.group(instruction{op: opEnter, path: path, node: node})
+		c.emit(instruction{op: opParallel, parallel: parallel, path: path, node: node})
+		c.group(instruction{op: opExit, path: path, node: node})
+	case IfGroup:
+		c.conditional(node, path, []Condition{pipe.Predicate}, []PipeGroup{pipe.Then}, pipe.Else)
+	case SwitchGroup:
+		predicates := make([]Condition, len(pipe.Cases))
+		groups := make([]PipeGroup, len(pipe.Cases))
+
+		for i, c := range pipe.Cases {
+			predicates[i], groups[i] = c.Predicate, c.Pipes
+		}
+
+		c.conditional(node, path, predicates, groups, pipe.Default)
+	default:
+		// unreachable, tree is validated before compilation
+		panic("handler.compile: unsupported pipe type at " + path.String())
+	}
+}
+
+// array compiles pipes of []Pipe or PipeArray
+func (c *compiler) array(node interface{}, pipes []interface{}, path Path) {
+	c.group(instruction{op: opEnter, path: path, node: node})
+
+	first := len(c.plan)
+
+	for i, p := range pipes {
+		c.emit(pipeInstruction(p, 0, true, childPath(path, i)))
+	}
+
+	exit := c.group(instruction{op: opExit, path: path, node: node})
+
+	// aborted pipe skips other pipes of the array
+	for i := first; i < exit; i++ {
+		c.plan[i].abort = exit
+	}
+}
+
+// pipeInstruction returns instruction what calls Pipe, ContextPipe or pipe of NamedPipe
+func pipeInstruction(node interface{}, abort int, replace bool, path Path) instruction {
+	i := instruction{op: opPipe, abort: abort, replace: replace, path: path, node: node}
+
+	pipe := node
+
+	if named, ok := node.(NamedPipe); ok {
+		pipe = named.Pipe
+	}
+
+	switch pipe := pipe.(type) {
+	case Pipe:
+		i.pipe = pipe
+	case ContextPipe:
+		i.contextPipe = pipe
+	}
+
+	return i
+}
+
+func (c *compiler) children(pipes PipeGroup, path Path) {
+	for i, pipe := range pipes {
+		c.node(pipe, childPath(path, i))
+	}
+}
+
+// childPath returns copy of path with appended index
+func childPath(path Path, i int) Path {
+	child := make(Path, len(path), len(path)+1)
+	copy(child, path)
+
+	return append(child, i)
+}
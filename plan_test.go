@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Compile_NestedGroups_ExpectFlatPlanWithAbortTargets(t *testing.T) {
+	pipes := PipeGroup{
+		nopPipe,
+		[]Pipe{nopPipe, nopPipe},
+		Timeout(time.Second, PipeGroup{nopPipe}),
+	}
+
+	plan := compile(pipes, true)
+
+	type step struct {
+		op      opcode
+		abort   int
+		replace bool
+		path    string
+	}
+
+	var steps []step
+	for _, i := range plan {
+		steps = append(steps, step{i.op, i.abort, i.replace, i.path.String()})
+	}
+
+	assert.Equal(t, []step{
+		{opEnter, 0, false, ""},
+		{opPipe, halt, false, "[0]"},
+		{opEnter, 0, false, "[1]"},
+		{opPipe, 5, true, "[1][0]"},
+		{opPipe, 5, true, "[1][1]"},
+		{opExit, 0, false, "[1]"},
+		{opEnter, 0, false, "[2]"},
+		{opPipe, halt, false, "[2][0]"},
+		{opExit, 0, false, "[2]"},
+		{opExit, 0, false, ""},
+	}, steps)
+
+	assert.Equal(t, time.Second, plan[6].timeout)
+	assert.Equal(t, time.Second, plan[8].timeout)
+
+	steps = nil
+	for _, i := range compile(pipes, false) {
+		steps = append(steps, step{i.op, i.abort, i.replace, i.path.String()})
+	}
+
+	assert.Equal(t, []step{
+		{opPipe, halt, false, "[0]"},
+		{opPipe, 3, true, "[1][0]"},
+		{opPipe, 3, true, "[1][1]"},
+		{opEnter, 0, false, "[2]"},
+		{opPipe, halt, false, "[2][0]"},
+		{opExit, 0, false, "[2]"},
+	}, steps, "groups without timeout are not entered when plan is not observed")
+}
+
+func Test_Handler_PipeInArrayReturnsNilValue_ExpectNextGroupExecuted(t *testing.T) {
+	var returnsNilPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, nil
+	}
+
+	var executed []int
+	step := func(n int) Pipe {
+		return func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+			executed = append(executed, n)
+
+			return &v, nil
+		}
+	}
+
+	pipes := PipeGroup{
+		[]Pipe{step(1), returnsNilPipe, step(2)},
+		PipeGroup{
+			[]Pipe{returnsNilPipe, step(3)},
+			step(4),
+		},
+		step(5),
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 4, 5}, executed)
+}
+
+func Test_Handler_PipeInGroupReturnsValue_ExpectInstanceNotReplaced(t *testing.T) {
+	var replacing Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		replaced := reflect.ValueOf(mockStruct{Field1: "replaced"})
+
+		return &replaced, nil
+	}
+
+	var fields []string
+	var capture Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		fields = append(fields, v.Interface().(mockStruct).Field1)
+
+		return &v, nil
+	}
+
+	h, err := New(PipeGroup{replacing, capture, []Pipe{replacing, capture}}, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "replaced"}, fields)
+}
+
+// recursiveHandler is pipe tree walker what was used before plan compilation,
+// kept as baseline for benchmarks of executor
+func recursiveHandler(pipesGroup PipeGroup, ctor func() reflect.Value) GenericHandlerFunc {
+	return func(args ...interface{}) error {
+		instance := ctor()
+
+		var executePipesArray func(pipes []Pipe) error
+		var executePipes func(pipes interface{}) (*reflect.Value, error)
+
+		executePipesArray = func(pipes []Pipe) error {
+			for _, pipe := range pipes {
+				instancePtr, err := executePipes(pipe)
+
+				if err != nil {
+					return err
+				}
+
+				if instancePtr == nil {
+					return nil
+				}
+
+				instance = *instancePtr
+			}
+
+			return nil
+		}
+
+		executePipes = func(pipes interface{}) (*reflect.Value, error) {
+			switch pipe := pipes.(type) {
+			case Pipe:
+				return pipe(instance, args...)
+			case []Pipe:
+				err := executePipesArray(pipe)
+				if err != nil {
+					return nil, err
+				}
+
+				return &instance, nil
+			case PipeGroup:
+				var err error
+				var v *reflect.Value
+
+				for _, group := range pipe {
+					v, err = executePipes(group)
+
+					if err != nil {
+						return nil, err
+					}
+
+					if v == AbortPipeGroup {
+						break
+					}
+				}
+
+				return v, err
+			}
+
+			panic("Wrong type: " + reflect.TypeOf(pipes).Name())
+		}
+
+		_, err := executePipes(pipesGroup)
+
+		return err
+	}
+}
+
+func benchmarkPipes() (PipeGroup, func() reflect.Value) {
+	instance := reflect.ValueOf(&mockStruct{})
+
+	// pipe doesn't allocate, so only executor is measured
+	var pipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return &instance, nil
+	}
+
+	pipes := PipeGroup{
+		[]Pipe{pipe, pipe},
+		[]Pipe{pipe, pipe},
+		PipeGroup{
+			[]Pipe{pipe, pipe},
+			PipeGroup{
+				[]Pipe{pipe, pipe},
+				PipeGroup{
+					[]Pipe{pipe, pipe},
+				},
+			},
+		},
+	}
+
+	return pipes, func() reflect.Value {
+		return instance
+	}
+}
+
+func Benchmark_Handler_CompiledPlan(b *testing.B) {
+	pipes, ctor := benchmarkPipes()
+
+	h, err := New(pipes, ctor, func(f GenericHandlerFunc) interface{} {
+		return f
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	handler := h.Handler().(GenericHandlerFunc)
+	ctx := &mockContext{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = handler(ctx)
+	}
+}
+
+func Benchmark_Handler_RecursiveWalker(b *testing.B) {
+	pipes, ctor := benchmarkPipes()
+
+	handler := recursiveHandler(pipes, ctor)
+	ctx := &mockContext{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = handler(ctx)
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ProblemContentType is content type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// Problem represents RFC 7807 problem details
+type Problem struct {
+	// Type is URI of problem type, "about:blank" if empty
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extensions are extension members of problem, like "errors" with invalid fields
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// NewProblem creates problem with status for err.
+//
+// Detail is message of *Error in chain of err. Messages of other errors
+// are used only for client errors, so internal details are not exposed.
+// Invalid fields of KindValidation error are "errors" extension member
+func NewProblem(status int, err error) *Problem {
+	problem := &Problem{
+		Title:  statusText(status),
+		Status: status,
+	}
+
+	var e *Error
+
+	switch {
+	case errors.As(err, &e):
+		problem.Detail = e.Message
+
+		if len(e.Fields) > 0 {
+			problem.Extensions = map[string]interface{}{"errors": e.Fields}
+		}
+	case err != nil && status < http.StatusInternalServerError:
+		problem.Detail = err.Error()
+	}
+
+	return problem
+}
+
+// ProblemOf creates problem with status of err, see StatusCode
+func ProblemOf(err error) *Problem {
+	return NewProblem(StatusCode(err), err)
+}
+
+// MarshalJSON writes extension members next to standard members
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+
+	members := map[string]interface{}{}
+
+	for name, value := range p.Extensions {
+		members[name] = value
+	}
+
+	standard, err := json.Marshal((*problem)(p))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(members) == 0 {
+		return standard, nil
+	}
+
+	if err := json.Unmarshal(standard, &members); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(members)
+}
+
+func statusText(status int) string {
+	if status == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+
+	return http.StatusText(status)
+}
+
+// ProblemWriter writes problem details to response, it's implemented by framework adapters.
+// args are arguments passed to generic handler by converter
+type ProblemWriter interface {
+	Problem(problem *Problem, args ...interface{}) error
+}
+
+// ProblemConfig configures conversion of errors to problem details
+type ProblemConfig struct {
+	// Writer writes problem to response
+	Writer ProblemWriter
+
+	// Type returns URI of problem type by kind of error,
+	// type is omitted what means "about:blank" if nil
+	Type func(kind Kind) string
+
+	// Extend adds extension members to problem, like trace id.
+	// ctx is context of request with values added by hooks, like span of handler
+	Extend func(ctx context.Context, problem *Problem, err error)
+}
+
+// WithProblems makes handler write errors returned by pipes, constructor,
+// injector and recovered panics as problem details, see ProblemOf.
+// Handler returns nil if problem is written and original error otherwise
+//
+// Example:
+//
+//	handler.New(pipes, action.GetArticle{}, nethttp.Converter, handler.WithProblems(handler.ProblemConfig{
+//		Writer: nethttp.Adapter{},
+//		Extend: otelhandler.TraceID,
+//	}))
+func WithProblems(config ProblemConfig) Option {
+	return func(h *handler) {
+		h.problems = &config
+	}
+}
+
+// write writes err as problem details
+func (c *ProblemConfig) write(ctx context.Context, err error, args []interface{}) error {
+	problem := ProblemOf(err)
+
+	if c.Type != nil {
+		problem.Type = c.Type(KindOf(err))
+	}
+
+	if c.Extend != nil {
+		c.Extend(ctx, problem, err)
+	}
+
+	if c.Writer.Problem(problem, args...) != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProblemWriter struct {
+	problem *Problem
+	err     error
+}
+
+func (w *mockProblemWriter) Problem(problem *Problem, args ...interface{}) error {
+	w.problem = problem
+
+	return w.err
+}
+
+func Test_ProblemOf_ExpectDetailAndExtensions(t *testing.T) {
+	problem := ProblemOf(Validation(FieldError{Field: "title", Message: "required"}))
+
+	body, err := json.Marshal(problem)
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "request is invalid",
+		"errors": [{"field": "title", "message": "required"}]
+	}`, string(body))
+}
+
+func Test_ProblemOf_InternalError_ExpectDetailHidden(t *testing.T) {
+	assert.Equal(t, &Problem{Title: "Internal Server Error", Status: 500}, ProblemOf(errors.New("sql: syntax error")))
+	assert.Equal(t, &Problem{Title: "Bad Request", Status: 400, Detail: "bad body"}, NewProblem(400, errors.New("bad body")))
+	assert.Equal(t, "Client Closed Request", ProblemOf(context.Canceled).Title)
+}
+
+func Test_Handler_WithProblems_ExpectErrorWrittenAsProblem(t *testing.T) {
+	writer := &mockProblemWriter{}
+
+	var failingPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return AbortPipeGroup, NotFound("article not found")
+	}
+
+	h, err := New(PipeGroup{failingPipe}, mockStruct{}, converterMock, WithProblems(ProblemConfig{
+		Writer: writer,
+		Type: func(kind Kind) string {
+			return "https://example.com/problems/" + kind.String()
+		},
+		Extend: func(ctx context.Context, problem *Problem, err error) {
+			problem.Extensions = map[string]interface{}{"pipe": err.(*Error).Path.String()}
+		},
+	}))
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.Handler().(func(*mockContext) error)(&mockContext{}))
+	assert.Equal(t, &Problem{
+		Type:       "https://example.com/problems/not found",
+		Title:      "Not Found",
+		Status:     404,
+		Detail:     "article not found",
+		Extensions: map[string]interface{}{"pipe": "[0]"},
+	}, writer.problem)
+}
+
+func Test_Handler_WithProblems_WriterFails_ExpectOriginalError(t *testing.T) {
+	mockError := errors.New("constructor failed")
+
+	writer := &mockProblemWriter{err: errors.New("already written")}
+
+	h, err := New(PipeGroup{nopPipe}, func() (*mockStruct, error) {
+		return nil, mockError
+	}, converterMock, WithProblems(ProblemConfig{Writer: writer}))
+	assert.NoError(t, err)
+
+	assert.Equal(t, mockError, h.Handler().(func(*mockContext) error)(&mockContext{}))
+	assert.Equal(t, &Problem{Title: "Internal Server Error", Status: 500}, writer.problem)
+}
+
+func Test_New_WithProblemsWithoutWriter_ExpectError(t *testing.T) {
+	_, err := New(PipeGroup{nopPipe}, mockStruct{}, converterMock, WithProblems(ProblemConfig{}))
+
+	assert.Equal(t, ErrorProblemWriterNil, err)
+}
@@ -0,0 +1,263 @@
+// Package prommetrics provides handler.MetricsCollector what exposes
+// metrics in Prometheus text exposition format without external dependencies
+package prommetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mykytanikitenko/go-handle"
+)
+
+// DefaultBuckets are default histogram buckets in seconds
+var DefaultBuckets = []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// ContentType is content type of text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Config configures collector.
+// Zero values are replaced with defaults
+type Config struct {
+	// Namespace is prefix of metric names, "handler" by default
+	Namespace string
+
+	// Buckets of duration histograms in seconds, DefaultBuckets by default
+	Buckets []float64
+}
+
+// Collector collects metrics of handlers and pipes:
+//
+//	<namespace>_duration_seconds{handler}            histogram
+//	<namespace>_errors_total{handler}                counter
+//	<namespace>_aborts_total{handler}                counter
+//	<namespace>_pipe_duration_seconds{handler,pipe}  histogram
+//	<namespace>_pipe_errors_total{handler,pipe}      counter
+//	<namespace>_pipe_aborts_total{handler,pipe}      counter
+//
+// Collector is http.Handler what writes metrics for scraping
+type Collector struct {
+	config Config
+
+	mu       sync.Mutex
+	handlers map[labels]*series
+	pipes    map[labels]*series
+}
+
+var _ handler.MetricsCollector = (*Collector)(nil)
+
+// labels represents label values of series
+type labels struct {
+	handler string
+	pipe    string
+}
+
+// series holds histogram and counters of handler or pipe
+type series struct {
+	// counts of observations by bucket, not cumulative
+	buckets []uint64
+	count   uint64
+	sum     float64
+
+	errors uint64
+	aborts uint64
+}
+
+// New creates collector
+func New(config Config) *Collector {
+	if config.Namespace == "" {
+		config.Namespace = "handler"
+	}
+
+	if config.Buckets == nil {
+		config.Buckets = DefaultBuckets
+	}
+
+	config.Buckets = append([]float64{}, config.Buckets...)
+	sort.Float64s(config.Buckets)
+
+	return &Collector{
+		config:   config,
+		handlers: map[labels]*series{},
+		pipes:    map[labels]*series{},
+	}
+}
+
+func (c *Collector) ObserveHandler(handler string, duration time.Duration, outcome handler.Outcome) {
+	c.observe(c.handlers, labels{handler: handler}, duration, outcome)
+}
+
+func (c *Collector) ObservePipe(handler, pipe string, duration time.Duration, outcome handler.Outcome) {
+	c.observe(c.pipes, labels{handler: handler, pipe: pipe}, duration, outcome)
+}
+
+func (c *Collector) observe(m map[labels]*series, key labels, duration time.Duration, outcome handler.Outcome) {
+	seconds := duration.Seconds()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := m[key]
+	if s == nil {
+		s = &series{buckets: make([]uint64, len(c.config.Buckets))}
+		m[key] = s
+	}
+
+	if i := sort.SearchFloat64s(c.config.Buckets, seconds); i < len(s.buckets) {
+		s.buckets[i]++
+	}
+
+	s.count++
+	s.sum += seconds
+
+	switch outcome {
+	case handler.OutcomeError:
+		s.errors++
+	case handler.OutcomeAborted:
+		s.aborts++
+	}
+}
+
+// ServeHTTP writes metrics in text exposition format
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+
+	_, _ = c.WriteTo(w)
+}
+
+// WriteTo writes metrics in text exposition format
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter := &countingWriter{w: w}
+	b := bufio.NewWriter(counter)
+
+	ns := c.config.Namespace
+
+	c.writeHistogram(b, ns+"_duration_seconds", "Duration of handler execution.", c.handlers)
+	c.writeCounter(b, ns+"_errors_total", "Handler executions what returned error.", c.handlers, errorsOf)
+	c.writeCounter(b, ns+"_aborts_total", "Handler executions what were aborted by pipe.", c.handlers, abortsOf)
+
+	c.writeHistogram(b, ns+"_pipe_duration_seconds", "Duration of pipe execution.", c.pipes)
+	c.writeCounter(b, ns+"_pipe_errors_total", "Pipe executions what returned error.", c.pipes, errorsOf)
+	c.writeCounter(b, ns+"_pipe_aborts_total", "Pipe executions what returned AbortPipeGroup.", c.pipes, abortsOf)
+
+	err := b.Flush()
+
+	return counter.n, err
+}
+
+func errorsOf(s *series) uint64 {
+	return s.errors
+}
+
+func abortsOf(s *series) uint64 {
+	return s.aborts
+}
+
+func (c *Collector) writeHistogram(w *bufio.Writer, name, help string, m map[labels]*series) {
+	if len(m) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+
+	for _, key := range sortedKeys(m) {
+		s := m[key]
+
+		var cumulative uint64
+
+		for i, bound := range c.config.Buckets {
+			cumulative += s.buckets[i]
+
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, key.format("le", formatFloat(bound)), cumulative)
+		}
+
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, key.format("le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", name, key.format("", ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", name, key.format("", ""), s.count)
+	}
+}
+
+func (c *Collector) writeCounter(w *bufio.Writer, name, help string, m map[labels]*series, value func(s *series) uint64) {
+	if len(m) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+
+	for _, key := range sortedKeys(m) {
+		fmt.Fprintf(w, "%s%s %d\n", name, key.format("", ""), value(m[key]))
+	}
+}
+
+func sortedKeys(m map[labels]*series) []labels {
+	keys := make([]labels, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].handler != keys[j].handler {
+			return keys[i].handler < keys[j].handler
+		}
+
+		return keys[i].pipe < keys[j].pipe
+	})
+
+	return keys
+}
+
+// format returns labels like {handler="...",pipe="..."} with extra label
+func (l labels) format(extraName, extraValue string) string {
+	pairs := []string{`handler="` + escape(l.handler) + `"`}
+
+	if l.pipe != "" {
+		pairs = append(pairs, `pipe="`+escape(l.pipe)+`"`)
+	}
+
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countingWriter counts written bytes
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
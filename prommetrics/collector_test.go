@@ -0,0 +1,81 @@
+package prommetrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mykytanikitenko/go-handle"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockHandler struct{}
+
+func Test_Collector_ExpectTextExposition(t *testing.T) {
+	c := New(Config{Namespace: "api", Buckets: []float64{1, 0.1}})
+
+	c.ObservePipe("action.GetArticles", "bind", 50*time.Millisecond, handler.OutcomeOK)
+	c.ObservePipe("action.GetArticles", "bind", 2*time.Second, handler.OutcomeAborted)
+	c.ObservePipe("action.GetArticles", `say "hi"`, 500*time.Millisecond, handler.OutcomeError)
+	c.ObserveHandler("action.GetArticles", time.Second, handler.OutcomeError)
+
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP api_duration_seconds Duration of handler execution.
+# TYPE api_duration_seconds histogram
+api_duration_seconds_bucket{handler="action.GetArticles",le="0.1"} 0
+api_duration_seconds_bucket{handler="action.GetArticles",le="1"} 1
+api_duration_seconds_bucket{handler="action.GetArticles",le="+Inf"} 1
+api_duration_seconds_sum{handler="action.GetArticles"} 1
+api_duration_seconds_count{handler="action.GetArticles"} 1
+# HELP api_errors_total Handler executions what returned error.
+# TYPE api_errors_total counter
+api_errors_total{handler="action.GetArticles"} 1
+# HELP api_aborts_total Handler executions what were aborted by pipe.
+# TYPE api_aborts_total counter
+api_aborts_total{handler="action.GetArticles"} 0
+# HELP api_pipe_duration_seconds Duration of pipe execution.
+# TYPE api_pipe_duration_seconds histogram
+api_pipe_duration_seconds_bucket{handler="action.GetArticles",pipe="bind",le="0.1"} 1
+api_pipe_duration_seconds_bucket{handler="action.GetArticles",pipe="bind",le="1"} 1
+api_pipe_duration_seconds_bucket{handler="action.GetArticles",pipe="bind",le="+Inf"} 2
+api_pipe_duration_seconds_sum{handler="action.GetArticles",pipe="bind"} 2.05
+api_pipe_duration_seconds_count{handler="action.GetArticles",pipe="bind"} 2
+api_pipe_duration_seconds_bucket{handler="action.GetArticles",pipe="say \"hi\"",le="0.1"} 0
+api_pipe_duration_seconds_bucket{handler="action.GetArticles",pipe="say \"hi\"",le="1"} 1
+api_pipe_duration_seconds_bucket{handler="action.GetArticles",pipe="say \"hi\"",le="+Inf"} 1
+api_pipe_duration_seconds_sum{handler="action.GetArticles",pipe="say \"hi\""} 0.5
+api_pipe_duration_seconds_count{handler="action.GetArticles",pipe="say \"hi\""} 1
+# HELP api_pipe_errors_total Pipe executions what returned error.
+# TYPE api_pipe_errors_total counter
+api_pipe_errors_total{handler="action.GetArticles",pipe="bind"} 0
+api_pipe_errors_total{handler="action.GetArticles",pipe="say \"hi\""} 1
+# HELP api_pipe_aborts_total Pipe executions what returned AbortPipeGroup.
+# TYPE api_pipe_aborts_total counter
+api_pipe_aborts_total{handler="action.GetArticles",pipe="bind"} 1
+api_pipe_aborts_total{handler="action.GetArticles",pipe="say \"hi\""} 0
+`, w.Body.String())
+}
+
+func Test_Collector_WithMetrics_ExpectPipesOfHandlerCollected(t *testing.T) {
+	c := New(Config{})
+
+	var failingPipe handler.Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return handler.AbortPipeGroup, errors.New("failed")
+	}
+
+	h, err := handler.New(handler.PipeGroup{handler.Named("fail", failingPipe)}, mockHandler{}, func(f handler.GenericHandlerFunc) interface{} {
+		return f
+	}, handler.WithMetrics(c))
+	assert.NoError(t, err)
+
+	assert.Error(t, h.Handler().(handler.GenericHandlerFunc)())
+
+	assert.Equal(t, uint64(1), c.pipes[labels{handler: "prommetrics.mockHandler", pipe: "fail"}].errors)
+	assert.Equal(t, uint64(1), c.handlers[labels{handler: "prommetrics.mockHandler"}].errors)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Path represents path of pipe in PipeGroup tree,
+// it's indexes of pipe and groups what contain it
+type Path []int
+
+// String returns path like [0][1]
+func (p Path) String() string {
+	var b strings.Builder
+
+	for _, i := range p {
+		b.WriteString("[" + strconv.Itoa(i) + "]")
+	}
+
+	return b.String()
+}
+
+// PipeError is returned by generic handler when pipe panicked
+// and handler was created with WithRecovery option
+type PipeError struct {
+	// Pipe is identity of panicked pipe
+	Pipe string
+
+	// Path is path of panicked pipe in PipeGroup tree,
+	// nil if handler constructor panicked.
+	// Panicked predicate of IfGroup or SwitchGroup has path of group what it chooses,
+	// like [0][1] for predicate of the second case, and Pipe like "predicate IsForm"
+	Path Path
+
+	// Stack is stack trace of panicked goroutine
+	Stack []byte
+
+	// Recovered is value passed to panic
+	Recovered interface{}
+}
+
+func (e *PipeError) Error() string {
+	if e.Path == nil {
+		return fmt.Sprintf("handler: constructor panicked: %v", e.Recovered)
+	}
+
+	return fmt.Sprintf("handler: pipe %s at %s panicked: %v", e.Pipe, e.Path, e.Recovered)
+}
+
+// Unwrap returns recovered value if it's error
+func (e *PipeError) Unwrap() error {
+	err, _ := e.Recovered.(error)
+
+	return err
+}
+
+// WithRecovery makes generic handler recover panics of pipes
+// and return them as *PipeError
+func WithRecovery() Option {
+	return func(h *handler) {
+		h.recovery = true
+	}
+}
+
+// recover converts panic to *PipeError
+func (e *execution) recover(err *error) {
+	recovered := recover()
+
+	if recovered == nil {
+		return
+	}
+
+	pipeErr := e.pipeError(recovered)
+
+	// panicked pipe observed by hook
+	if e.pipeCtx != nil {
+		e.hook.AfterPipe(e.pipeCtx, e.steps[e.pc], Result{
+			Duration: time.Since(e.pipeStart),
+			Err:      pipeErr,
+		})
+	}
+
+	e.unwind(pipeErr)
+
+	*err = pipeErr
+}
+
+// pipeError creates *PipeError of current instruction with stack of panicked goroutine
+func (e *execution) pipeError(recovered interface{}) *PipeError {
+	pipeErr := &PipeError{
+		Stack:     debug.Stack(),
+		Recovered: recovered,
+	}
+
+	if e.pc == constructing {
+		return pipeErr
+	}
+
+	i := e.plan[e.pc]
+
+	pipeErr.Pipe = pipeName(i.node)
+	pipeErr.Path = append(Path(nil), i.path...)
+
+	// predicate is placed at path of group what it chooses
+	if i.op == opSwitch {
+		pipeErr.Pipe = "predicate " + predicateLabel(i.predicates[e.predicate])
+		pipeErr.Path = childPath(i.path, e.predicate)
+	}
+
+	return pipeErr
+}
+
+// pipeName returns name of named pipe or pipe func,
+// or type name of non-func value
+func pipeName(pipe interface{}) string {
+	switch p := pipe.(type) {
+	case NamedPipe:
+		return p.Name
+	case NamedCondition:
+		return p.Name
+	}
+
+	v := reflect.ValueOf(pipe)
+
+	if v.Kind() != reflect.Func {
+		return fmt.Sprintf("%T", pipe)
+	}
+
+	if f := runtime.FuncForPC(v.Pointer()); f != nil {
+		return f.Name()
+	}
+
+	return v.Type().String()
+}
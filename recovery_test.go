@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Handler_WithRecovery_PipePanics_ExpectPipeError(t *testing.T) {
+	var panicPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		panic("pipe panicked")
+	}
+
+	pipes := PipeGroup{
+		[]Pipe{nopPipe},
+		PipeGroup{
+			[]Pipe{nopPipe, panicPipe},
+		},
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock, WithRecovery())
+	assert.NoError(t, err)
+
+	var handlerErr error
+
+	assert.NotPanics(t, func() {
+		handlerErr = h.Handler().(func(*mockContext) error)(&mockContext{})
+	})
+
+	var pipeError *PipeError
+
+	assert.True(t, errors.As(handlerErr, &pipeError))
+	assert.Equal(t, Path{1, 0, 1}, pipeError.Path)
+	assert.Equal(t, "pipe panicked", pipeError.Recovered)
+	assert.Contains(t, pipeError.Pipe, "Test_Handler_WithRecovery_PipePanics_ExpectPipeError")
+	assert.NotEmpty(t, pipeError.Stack)
+	assert.Equal(t, "handler: pipe "+pipeError.Pipe+" at [1][0][1] panicked: pipe panicked", pipeError.Error())
+}
+
+func Test_Handler_WithRecovery_PanicWithError_ExpectUnwrapped(t *testing.T) {
+	mockError := errors.New("some error")
+
+	var panicPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		panic(mockError)
+	}
+
+	h, err := New(PipeGroup{panicPipe}, mockStruct{}, converterMock, WithRecovery())
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.True(t, errors.Is(err, mockError))
+}
+
+func Test_Handler_WithoutRecovery_PipePanics_ExpectPanic(t *testing.T) {
+	var panicPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		panic("pipe panicked")
+	}
+
+	h, err := New(PipeGroup{panicPipe}, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+
+	assert.Panics(t, func() {
+		h.Handler().(func(*mockContext) error)(&mockContext{})
+	})
+}
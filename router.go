@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Route represents handler bound to method and path
+type Route struct {
+	// HTTP method, like "GET".
+	// Method of Endpoint field of handler type is used if empty
+	Method string
+
+	// Path in syntax of framework, like /articles/:id or /articles/{id}.
+	// Path of Endpoint field of handler type is used if empty
+	Path string
+
+	// Value passed to New: struct, pointer to struct or constructor
+	T interface{}
+
+	// Pipes of route, pipes of router are used if nil
+	Pipes PipeGroup
+
+	// Options appended to options of router
+	Options []Option
+}
+
+// RouteInfo represents registered route
+type RouteInfo struct {
+	Route
+
+	// Struct type of handler instance
+	Type reflect.Type
+
+	Handler Handler
+}
+
+// Mounter mounts handlers to framework
+type Mounter interface {
+	// Mount registers handler returned by converter with method and path
+	Mount(method, path string, handler interface{}) error
+}
+
+// MounterFunc is a func what implements Mounter
+//
+// Example:
+//
+//	router.Mount(handler.MounterFunc(func(method, path string, h interface{}) error {
+//		e.Add(method, path, h.(echo.HandlerFunc))
+//
+//		return nil
+//	}))
+type MounterFunc func(method, path string, handler interface{}) error
+
+func (f MounterFunc) Mount(method, path string, handler interface{}) error {
+	return f(method, path, handler)
+}
+
+// Router is a registry of routes what share pipes, converter and options.
+// Handlers are created on registration, so invalid handlers and duplicate
+// routes are reported at startup
+//
+// Example:
+//
+//	router := handler.NewRouter(nethttp.Pipes, nethttp.Converter)
+//
+//	err := router.Handle(
+//		handler.Route{Method: "GET", Path: "/articles", T: action.GetArticles{}},
+//		handler.Route{Method: "POST", Path: "/articles", T: action.CreateArticle{}},
+//	)
+//
+//	err = router.Mount(nethttp.Mounter(mux))
+type Router struct {
+	pipes     PipeGroup
+	converter Converter
+	options   []Option
+
+	mu     sync.RWMutex
+	routes []RouteInfo
+
+	// registered routes by method and path pattern
+	patterns map[string]RouteInfo
+}
+
+// NewRouter creates router with default pipes, converter and options of routes
+func NewRouter(pipes PipeGroup, converter Converter, options ...Option) *Router {
+	return &Router{
+		pipes:     pipes,
+		converter: converter,
+		options:   options,
+		patterns:  map[string]RouteInfo{},
+	}
+}
+
+// Handle registers routes
+func (r *Router) Handle(routes ...Route) error {
+	for _, route := range routes {
+		if err := r.handle(route); err != nil {
+			return fmt.Errorf("%w: route %s", err, route)
+		}
+	}
+
+	return nil
+}
+
+// String returns method and path of route,
+// or type of handler if route is declared by Endpoint field
+func (r Route) String() string {
+	if r.Method == "" && r.Path == "" {
+		return fmt.Sprintf("%T", r.T)
+	}
+
+	return r.Method + " " + r.Path
+}
+
+func (r *Router) handle(route Route) error {
+	pipes := route.Pipes
+	if pipes == nil {
+		pipes = r.pipes
+	}
+
+	options := append(append([]Option{}, r.options...), route.Options...)
+
+	h, err := New(pipes, route.T, r.converter, options...)
+	if err != nil {
+		return err
+	}
+
+	// route declared by Endpoint field of handler type
+	if route.Method == "" {
+		route.Method = h.metadata.Method
+	}
+
+	if route.Path == "" {
+		route.Path = h.metadata.Path
+	}
+
+	route.Method = strings.ToUpper(route.Method)
+
+	if route.Method == "" || route.Path == "" {
+		return ErrorRouteInvalid
+	}
+
+	pattern := route.Method + " " + routePattern(route.Path)
+	info := RouteInfo{Route: route, Type: h.typ, Handler: h}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, exists := r.patterns[pattern]; exists {
+		return fmt.Errorf("%w with %s %s", ErrorRouteDuplicate, existing.Method, existing.Path)
+	}
+
+	r.patterns[pattern] = info
+	r.routes = append(r.routes, info)
+
+	return nil
+}
+
+// Register registers routes declared by Endpoint field of handler types
+//
+// Example:
+//
+//	err := router.Register(action.GetArticles{}, action.CreateArticle{})
+func (r *Router) Register(ts ...interface{}) error {
+	for _, t := range ts {
+		if err := r.Handle(Route{T: t}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Routes returns registered routes in order of registration
+func (r *Router) Routes() []RouteInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]RouteInfo{}, r.routes...)
+}
+
+// Lookup returns route registered with method and path
+func (r *Router) Lookup(method, path string) (RouteInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	route, ok := r.patterns[strings.ToUpper(method)+" "+routePattern(path)]
+
+	return route, ok
+}
+
+// Mount mounts handlers of registered routes
+func (r *Router) Mount(m Mounter) error {
+	if m == nil {
+		return ErrorMounterNil
+	}
+
+	for _, route := range r.Routes() {
+		if err := m.Mount(route.Method, route.Path, route.Handler.Handler()); err != nil {
+			return fmt.Errorf("%w: route %s", err, route)
+		}
+	}
+
+	return nil
+}
+
+// routePattern replaces names of path parameters, so /articles/:id
+// and /articles/{name} are the same pattern
+func routePattern(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = "{}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockMount struct {
+	method, path string
+	handler      interface{}
+}
+
+func Test_Router_Handle_ExpectRoutesListedInOrder(t *testing.T) {
+	router := NewRouter(mockPipes, converterMock)
+
+	err := router.Handle(
+		Route{Method: "get", Path: "/articles", T: mockStruct{}},
+		Route{Method: "POST", Path: "/articles/:id", T: func() *mockInjectedHandler { return nil }},
+	)
+	assert.NoError(t, err)
+
+	routes := router.Routes()
+
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "GET", routes[0].Method)
+	assert.Equal(t, reflect.TypeOf(mockStruct{}), routes[0].Type)
+	assert.Equal(t, reflect.TypeOf(mockInjectedHandler{}), routes[1].Type)
+
+	route, ok := router.Lookup("POST", "/articles/{name}")
+
+	assert.True(t, ok)
+	assert.Equal(t, "/articles/:id", route.Path)
+}
+
+func Test_Router_Handle_DuplicateRoute_ExpectError(t *testing.T) {
+	router := NewRouter(mockPipes, converterMock)
+
+	assert.NoError(t, router.Handle(Route{Method: "GET", Path: "/articles/:id", T: mockStruct{}}))
+
+	err := router.Handle(Route{Method: "GET", Path: "/articles/{name}", T: mockStruct{}})
+
+	assert.True(t, errors.Is(err, ErrorRouteDuplicate))
+	assert.Contains(t, err.Error(), "GET /articles/:id")
+	assert.Len(t, router.Routes(), 1)
+}
+
+func Test_Router_Handle_InvalidRoute_ExpectError(t *testing.T) {
+	router := NewRouter(mockPipes, converterMock)
+
+	assert.True(t, errors.Is(router.Handle(Route{Path: "/", T: mockStruct{}}), ErrorRouteInvalid))
+	assert.True(t, errors.Is(router.Handle(Route{Method: "GET", Path: "/", T: 1}), ErrorInvalidConstructorType))
+}
+
+func Test_Router_Mount_ExpectRoutePipesOverrideRouterPipes(t *testing.T) {
+	var executed []string
+
+	pipe := func(name string) Pipe {
+		return func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+			executed = append(executed, name)
+
+			return &v, nil
+		}
+	}
+
+	router := NewRouter(PipeGroup{pipe("router")}, converterMock)
+
+	err := router.Handle(
+		Route{Method: "GET", Path: "/default", T: mockStruct{}},
+		Route{Method: "GET", Path: "/override", T: mockStruct{}, Pipes: PipeGroup{pipe("route")}},
+	)
+	assert.NoError(t, err)
+
+	var mounted []mockMount
+
+	err = router.Mount(MounterFunc(func(method, path string, handler interface{}) error {
+		mounted = append(mounted, mockMount{method, path, handler})
+
+		return nil
+	}))
+	assert.NoError(t, err)
+
+	for _, m := range mounted {
+		assert.NoError(t, m.handler.(func(*mockContext) error)(&mockContext{}))
+	}
+
+	assert.Equal(t, []string{"GET", "GET"}, []string{mounted[0].method, mounted[1].method})
+	assert.Equal(t, []string{"/default", "/override"}, []string{mounted[0].path, mounted[1].path})
+	assert.Equal(t, []string{"router", "route"}, executed)
+}
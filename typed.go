@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"reflect"
+)
+
+// TypedPipe represents a type-safe execution pipe over handler instance
+// of type T and context of type C, which is the first argument
+// passed to generic handler by converter.
+//
+// Returning false means that next pipe should not be called,
+// the same as returning AbortPipeGroup from Pipe
+//
+// Example:
+//
+//	var BindRequest handler.TypedPipe[GetArticles, echo.Context] = func(action *GetArticles, ctx echo.Context) (bool, error) {
+//		if err := ctx.Bind(&action.Request); err != nil {
+//			return false, err
+//		}
+//
+//		return true, nil
+//	}
+type TypedPipe[T any, C any] func(instance *T, ctx C) (bool, error)
+
+// Pipe converts typed pipe to Pipe, so it can be used in PipeGroup and []Pipe
+// together with reflect based pipes
+func (p TypedPipe[T, C]) Pipe() Pipe {
+	return func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		if len(args) == 0 {
+			return AbortPipeGroup, ErrorTypedPipeContext
+		}
+
+		ctx, ok := args[0].(C)
+		if !ok {
+			return AbortPipeGroup, ErrorTypedPipeContext
+		}
+
+		instance, ok := typedInstance[T](v)
+		if !ok {
+			return AbortPipeGroup, ErrorTypedPipeInstance
+		}
+
+		next, err := p(instance, ctx)
+
+		if !next {
+			return AbortPipeGroup, err
+		}
+
+		// instance was copied because passed value is not addressable
+		if v.Kind() == reflect.Struct && !v.CanAddr() {
+			return ContinuePipeGroup(reflect.ValueOf(instance).Elem()), err
+		}
+
+		return ContinuePipeGroup(v), err
+	}
+}
+
+// Typed converts typed pipes to []Pipe
+//
+// Example:
+//
+//	var ActionPipes = handler.PipeGroup{
+//		handler.Typed(BindRequest, ValidateRequest),
+//		handler.PipeArray{CallActionPipe},
+//	}
+func Typed[T any, C any](pipes ...TypedPipe[T, C]) []Pipe {
+	converted := make([]Pipe, 0, len(pipes))
+
+	for _, pipe := range pipes {
+		converted = append(converted, pipe.Pipe())
+	}
+
+	return converted
+}
+
+// typedInstance returns pointer to instance of type T held by v
+func typedInstance[T any](v reflect.Value) (*T, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		instance, ok := v.Interface().(*T)
+
+		return instance, ok && instance != nil
+	case reflect.Struct:
+		if v.Type() != reflect.TypeOf((*T)(nil)).Elem() {
+			return nil, false
+		}
+
+		if v.CanAddr() {
+			return v.Addr().Interface().(*T), true
+		}
+
+		// Value returned by constructor func is not addressable,
+		// so pipe gets a copy of it
+		instance := new(T)
+		reflect.ValueOf(instance).Elem().Set(v)
+
+		return instance, true
+	}
+
+	return nil, false
+}
+
+// TypedConverter returns converter what converts generic handler
+// to func(C) error
+func TypedConverter[C any]() Converter {
+	return func(f GenericHandlerFunc) interface{} {
+		return func(ctx C) error {
+			return f(ctx)
+		}
+	}
+}
+
+// TypedHandler represents handler with known instance type T
+// and context type C
+type TypedHandler[T any, C any] struct {
+	*handler
+}
+
+// NewTyped creates new Handler what creates instance of T with ctor
+// for each request and converts to func(C) error.
+// If ctor is nil, new(T) is used. Options are passed to New
+//
+// Example:
+//
+//	h, err := handler.NewTyped[GetArticles, echo.Context](ActionPipes, nil, handler.WithRecovery())
+//	e.GET("/articles", h.HandlerFunc())
+func NewTyped[T any, C any](pipes PipeGroup, ctor func() *T, options ...Option) (*TypedHandler[T, C], error) {
+	if ctor == nil {
+		ctor = func() *T {
+			return new(T)
+		}
+	}
+
+	h, err := New(pipes, ctor, TypedConverter[C](), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedHandler[T, C]{handler: h}, nil
+}
+
+// HandlerFunc returns final handler as func(C) error
+func (h *TypedHandler[T, C]) HandlerFunc() func(C) error {
+	return h.Handler().(func(C) error)
+}
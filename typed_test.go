@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TypedPipe_PointerInstance_ExpectInstanceModified(t *testing.T) {
+	var pipe TypedPipe[mockStruct, *mockContext] = func(instance *mockStruct, ctx *mockContext) (bool, error) {
+		instance.Field1 = "typed"
+
+		return true, nil
+	}
+
+	instance := &mockStruct{}
+
+	v, err := pipe.Pipe()(reflect.ValueOf(instance), &mockContext{})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, v)
+	assert.Equal(t, "typed", instance.Field1)
+}
+
+func Test_TypedPipe_NotAddressableStruct_ExpectCopyReturned(t *testing.T) {
+	var pipe TypedPipe[mockStruct, *mockContext] = func(instance *mockStruct, ctx *mockContext) (bool, error) {
+		instance.Field1 = "typed"
+
+		return true, nil
+	}
+
+	v, err := pipe.Pipe()(reflect.ValueOf(mockStruct{}), &mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, mockStruct{Field1: "typed"}, v.Interface())
+}
+
+func Test_TypedPipe_ReturnsFalse_ExpectAbortPipeGroup(t *testing.T) {
+	var pipe TypedPipe[mockStruct, *mockContext] = func(instance *mockStruct, ctx *mockContext) (bool, error) {
+		return false, nil
+	}
+
+	v, err := pipe.Pipe()(reflect.ValueOf(&mockStruct{}), &mockContext{})
+
+	assert.NoError(t, err)
+	assert.True(t, v == AbortPipeGroup)
+}
+
+func Test_TypedPipe_WrongContext_ExpectError(t *testing.T) {
+	var pipe TypedPipe[mockStruct, *mockContext] = func(instance *mockStruct, ctx *mockContext) (bool, error) {
+		return true, nil
+	}
+
+	_, err := pipe.Pipe()(reflect.ValueOf(&mockStruct{}), "not a context")
+	assert.Equal(t, ErrorTypedPipeContext, err)
+
+	_, err = pipe.Pipe()(reflect.ValueOf(&mockStruct{}))
+	assert.Equal(t, ErrorTypedPipeContext, err)
+}
+
+func Test_TypedPipe_WrongInstance_ExpectError(t *testing.T) {
+	var pipe TypedPipe[mockStruct, *mockContext] = func(instance *mockStruct, ctx *mockContext) (bool, error) {
+		return true, nil
+	}
+
+	_, err := pipe.Pipe()(reflect.ValueOf(&mockContext{}), &mockContext{})
+
+	assert.Equal(t, ErrorTypedPipeInstance, err)
+}
+
+func Test_NewTyped_MixedPipes_ExpectExecutedInOrder(t *testing.T) {
+	var steps []string
+
+	var typedPipe TypedPipe[mockStruct, *mockContext] = func(instance *mockStruct, ctx *mockContext) (bool, error) {
+		steps = append(steps, "typed")
+		instance.Field1 = "typed"
+
+		return true, nil
+	}
+
+	var reflectPipe Pipe = func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		steps = append(steps, "reflect")
+
+		assert.Equal(t, "typed", v.Interface().(*mockStruct).Field1)
+
+		return ContinuePipeGroup(v), nil
+	}
+
+	h, err := NewTyped[mockStruct, *mockContext](
+		PipeGroup{Typed(typedPipe), []Pipe{reflectPipe}},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	err = h.HandlerFunc()(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"typed", "reflect"}, steps)
+}
+
+func Test_NewTyped_PipeReturnsError_ErrorFallthroughHandler(t *testing.T) {
+	mockError := errors.New("some error appeared in typed pipe")
+
+	var pipe TypedPipe[mockStruct, *mockContext] = func(instance *mockStruct, ctx *mockContext) (bool, error) {
+		return false, mockError
+	}
+
+	h, err := NewTyped[mockStruct, *mockContext](PipeGroup{Typed(pipe)}, func() *mockStruct {
+		return &mockStruct{}
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, mockError, h.HandlerFunc()(&mockContext{}))
+}
+
+func Test_NewTyped_WithOptions_ExpectOptionsApplied(t *testing.T) {
+	var pipe TypedPipe[mockStruct, *mockContext] = func(instance *mockStruct, ctx *mockContext) (bool, error) {
+		panic("typed pipe panicked")
+	}
+
+	h, err := NewTyped[mockStruct, *mockContext](PipeGroup{Typed(pipe)}, nil, WithRecovery())
+	assert.NoError(t, err)
+
+	var pipeErr *PipeError
+
+	assert.ErrorAs(t, h.HandlerFunc()(&mockContext{}), &pipeErr)
+	assert.Equal(t, "typed pipe panicked", pipeErr.Recovered)
+}
+
+func Test_NewTyped_NonStructType_ExpectError(t *testing.T) {
+	_, err := NewTyped[int, *mockContext](mockPipes, nil)
+
+	assert.Equal(t, ErrorPointerNonStructType, err)
+}
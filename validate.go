@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// TreeError is returned by New when PipeGroup tree contains invalid element
+type TreeError struct {
+	// Path is path of invalid element in PipeGroup tree
+	Path Path
+
+	// Value is invalid element
+	Value interface{}
+
+	Err error
+}
+
+func (e *TreeError) Error() string {
+	if e.Err == ErrorUnsupportedPipeType {
+		return fmt.Sprintf("%s %T at pipesGroup%s", e.Err, e.Value, e.Path)
+	}
+
+	return fmt.Sprintf("%s at pipesGroup%s", e.Err, e.Path)
+}
+
+func (e *TreeError) Unwrap() error {
+	return e.Err
+}
+
+// validatePipes checks PipeGroup tree and returns its copy
+// where plain funcs are converted to Pipe and ContextPipe
+func validatePipes(pipes PipeGroup) (PipeGroup, error) {
+	return validateGroup(pipes, nil)
+}
+
+func validateGroup(pipes PipeGroup, path Path) (PipeGroup, error) {
+	if len(pipes) == 0 {
+		return nil, &TreeError{Path: path, Value: pipes, Err: ErrorPipeGroupEmpty}
+	}
+
+	group := make(PipeGroup, 0, len(pipes))
+
+	for i, pipe := range pipes {
+		validated, err := validatePipe(pipe, append(path[:len(path):len(path)], i))
+		if err != nil {
+			return nil, err
+		}
+
+		group = append(group, validated)
+	}
+
+	return group, nil
+}
+
+// validateArray checks that elements of PipeArray are pipes
+func validateArray(pipes PipeArray, path Path) (PipeArray, error) {
+	if len(pipes) == 0 {
+		return nil, &TreeError{Path: path, Value: pipes, Err: ErrorPipeGroupEmpty}
+	}
+
+	array := make(PipeArray, len(pipes))
+
+	for i, pipe := range pipes {
+		validated, err := validatePipe(pipe, childPath(path, i))
+		if err != nil {
+			return nil, err
+		}
+
+		switch validated.(type) {
+		case Pipe, ContextPipe, NamedPipe:
+			array[i] = validated
+		default:
+			return nil, &TreeError{Path: childPath(path, i), Value: pipe, Err: ErrorUnsupportedPipeType}
+		}
+	}
+
+	return array, nil
+}
+
+func validatePipe(pipe interface{}, path Path) (interface{}, error) {
+	switch pipe := pipe.(type) {
+	case nil:
+		return nil, &TreeError{Path: path, Err: ErrorPipeNil}
+	case func(reflect.Value, ...interface{}) (*reflect.Value, error):
+		return validatePipe(Pipe(pipe), path)
+	case func(context.Context, reflect.Value, ...interface{}) (*reflect.Value, error):
+		return validatePipe(ContextPipe(pipe), path)
+	case Pipe:
+		if pipe == nil {
+			return nil, &TreeError{Path: path, Value: pipe, Err: ErrorPipeNil}
+		}
+
+		return pipe, nil
+	case ContextPipe:
+		if pipe == nil {
+			return nil, &TreeError{Path: path, Value: pipe, Err: ErrorPipeNil}
+		}
+
+		return pipe, nil
+	case []Pipe:
+		if len(pipe) == 0 {
+			return nil, &TreeError{Path: path, Value: pipe, Err: ErrorPipeGroupEmpty}
+		}
+
+		for i, p := range pipe {
+			if p == nil {
+				return nil, &TreeError{Path: append(path, i), Value: p, Err: ErrorPipeNil}
+			}
+		}
+
+		return pipe, nil
+	case PipeArray:
+		return validateArray(pipe, path)
+	case NamedPipe:
+		return validateNamed(pipe, path)
+	case PipeGroup:
+		return validateGroup(pipe, path)
+	case TimeoutGroup:
+		if pipe.Timeout <= 0 {
+			return nil, &TreeError{Path: path, Value: pipe, Err: ErrorInvalidTimeout}
+		}
+
+		group, err := validateGroup(pipe.Pipes, path)
+		if err != nil {
+			return nil, err
+		}
+
+		return Timeout(pipe.Timeout, group), nil
+	case ParallelGroup:
+		return validateParallel(pipe, path)
+	case IfGroup:
+		return validateIf(pipe, path)
+	case SwitchGroup:
+		return validateSwitch(pipe, path)
+	}
+
+	return nil, &TreeError{Path: path, Value: pipe, Err: ErrorUnsupportedPipeType}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_New_NilPipe_ExpectPipeNilErrorWithPath(t *testing.T) {
+	pipes := PipeGroup{
+		[]Pipe{nopPipe},
+		PipeGroup{
+			[]Pipe{nopPipe, nil},
+		},
+	}
+
+	_, err := New(pipes, mockStruct{}, converterMock)
+
+	var treeError *TreeError
+
+	assert.True(t, errors.As(err, &treeError))
+	assert.Equal(t, ErrorPipeNil, treeError.Err)
+	assert.Equal(t, Path{1, 0, 1}, treeError.Path)
+	assert.Equal(t, "handler.New: pipe nil at pipesGroup[1][0][1]", err.Error())
+}
+
+func Test_New_NilInterfaceInGroup_ExpectPipeNilError(t *testing.T) {
+	_, err := New(PipeGroup{nopPipe, nil}, mockStruct{}, converterMock)
+
+	assert.True(t, errors.Is(err, ErrorPipeNil))
+}
+
+func Test_New_EmptyGroups_ExpectPipeGroupEmptyError(t *testing.T) {
+	t.Run("Empty root group", func(t *testing.T) {
+		_, err := New(PipeGroup{}, mockStruct{}, converterMock)
+
+		assert.True(t, errors.Is(err, ErrorPipeGroupEmpty))
+	})
+
+	t.Run("Empty nested group", func(t *testing.T) {
+		_, err := New(PipeGroup{nopPipe, PipeGroup{}}, mockStruct{}, converterMock)
+
+		assert.True(t, errors.Is(err, ErrorPipeGroupEmpty))
+	})
+
+	t.Run("Empty pipe array", func(t *testing.T) {
+		_, err := New(PipeGroup{[]Pipe{}}, mockStruct{}, converterMock)
+
+		assert.True(t, errors.Is(err, ErrorPipeGroupEmpty))
+	})
+
+	t.Run("Empty timeout group", func(t *testing.T) {
+		_, err := New(PipeGroup{Timeout(1, PipeGroup{})}, mockStruct{}, converterMock)
+
+		assert.True(t, errors.Is(err, ErrorPipeGroupEmpty))
+	})
+}
+
+func Test_New_UnsupportedPipeType_ExpectErrorWithTypeAndPath(t *testing.T) {
+	_, err := New(PipeGroup{nopPipe, PipeGroup{"pipe"}}, mockStruct{}, converterMock)
+
+	assert.True(t, errors.Is(err, ErrorUnsupportedPipeType))
+	assert.Equal(t, "handler.New: unsupported pipe type string at pipesGroup[1][0]", err.Error())
+}
+
+func Test_New_NotPositiveTimeout_ExpectInvalidTimeoutError(t *testing.T) {
+	_, err := New(PipeGroup{Timeout(0, PipeGroup{nopPipe})}, mockStruct{}, converterMock)
+
+	assert.True(t, errors.Is(err, ErrorInvalidTimeout))
+}
+
+func Test_New_PlainFuncs_ExpectConvertedAndExecuted(t *testing.T) {
+	var executed []string
+
+	pipes := PipeGroup{
+		func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+			executed = append(executed, "pipe")
+
+			return &v, nil
+		},
+		func(ctx context.Context, v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+			executed = append(executed, "context pipe")
+
+			return &v, nil
+		},
+	}
+
+	h, err := New(pipes, mockStruct{}, converterMock)
+	assert.NoError(t, err)
+
+	err = h.Handler().(func(*mockContext) error)(&mockContext{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pipe", "context pipe"}, executed)
+}
+
+func Test_New_ValidPipes_ExpectPassedGroupNotModified(t *testing.T) {
+	plain := func(v reflect.Value, args ...interface{}) (*reflect.Value, error) {
+		return &v, nil
+	}
+
+	pipes := PipeGroup{plain}
+
+	_, err := New(pipes, mockStruct{}, converterMock)
+
+	assert.NoError(t, err)
+	assert.IsType(t, plain, pipes[0])
+}